@@ -0,0 +1,114 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// openAssignment returns the developer's assignment on the task that has not
+// ended yet, or nil when they are not on it.
+func (t *Task) openAssignment(devName string) *Assignment {
+	for _, a := range t.Assignments {
+		if a.Developer == devName && a.End.IsZero() {
+			return a
+		}
+	}
+	return nil
+}
+
+// isAssigned reports whether the developer is currently on the task.
+func (t *Task) isAssigned(devName string) bool {
+	return t.openAssignment(devName) != nil
+}
+
+// wasInterrupted reports whether a developer was taken off the task before.
+func (t *Task) wasInterrupted(devName string) bool {
+	for _, a := range t.Assignments {
+		if a.Developer == devName && a.Interrupted {
+			return true
+		}
+	}
+	return false
+}
+
+// startAssignment puts a developer on a task from date. effort is what they
+// already delivered before the schedule start.
+func (t *Task) startAssignment(devName string, date time.Time, fraction, effort float64) {
+	t.Assignments = append(t.Assignments, &Assignment{
+		Task:      t.Name,
+		Developer: devName,
+		Start:     date,
+		Fraction:  fraction,
+		Effort:    effort,
+	})
+}
+
+// endAssignment closes an assignment when the developer's last recorded work
+// on it ended and adds the effort the ledger shows for it. An assignment with
+// no work recorded ends at end if it carries effort from before the schedule
+// start, and is dropped otherwise.
+func (t *Task) endAssignment(a *Assignment, end time.Time) {
+	var last time.Time
+	for _, entry := range t.Ledger {
+		if entry.Developer == a.Developer && !entry.Start.Before(a.Start) && entry.End.After(last) {
+			last = entry.End
+		}
+	}
+	if last.IsZero() && (a.Effort == 0 || end.Before(a.Start)) {
+		for i, other := range t.Assignments {
+			if other == a {
+				t.Assignments = append(t.Assignments[:i], t.Assignments[i+1:]...)
+				break
+			}
+		}
+		return
+	}
+	if !last.IsZero() {
+		end = last
+	}
+	a.End = end
+	a.Effort += t.effortSince(a.Developer, a.Start)
+}
+
+// effortSince sums the ledger entries of a developer starting from start on.
+func (t *Task) effortSince(devName string, start time.Time) float64 {
+	effort := 0.0
+	for _, entry := range t.Ledger {
+		if entry.Developer == devName && !entry.Start.Before(start) {
+			effort += entry.Effort
+		}
+	}
+	return effort
+}
+
+// endAssignments closes every open assignment on a finished task.
+func (t *Task) endAssignments() {
+	for _, a := range append([]*Assignment{}, t.Assignments...) {
+		if a.End.IsZero() {
+			t.endAssignment(a, t.EndTime)
+		}
+	}
+}
+
+// Assignments lists who worked on what and when, ordered by start date.
+// Assignments on unfinished tasks run to the task's projected end.
+func (s *Scheduler) Assignments() []Assignment {
+	var assignments []Assignment
+	for _, task := range s.tasks {
+		for _, a := range task.Assignments {
+			assignment := *a
+			if assignment.End.IsZero() {
+				assignment.End = task.EndTime
+				assignment.Effort += task.effortSince(a.Developer, a.Start)
+			}
+			assignments = append(assignments, assignment)
+		}
+	}
+	sort.SliceStable(assignments, func(i, j int) bool {
+		if !assignments[i].Start.Equal(assignments[j].Start) {
+			return assignments[i].Start.Before(assignments[j].Start)
+		}
+		return assignments[i].Developer < assignments[j].Developer
+	})
+	return assignments
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WorkWeek marks which weekdays are working days, indexed by time.Weekday.
+type WorkWeek [7]bool
+
+// defaultWorkWeek is Monday to Friday.
+var defaultWorkWeek = WorkWeek{time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// parseWorkWeek parses a pattern of comma separated weekdays and ranges such
+// as "Mon-Thu" or "Sun-Wed,Fri". Ranges may wrap around the end of the week.
+func parseWorkWeek(pattern string) (WorkWeek, error) {
+	var week WorkWeek
+	for _, part := range strings.Split(pattern, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := parseWeekday(bounds[0])
+		if err != nil {
+			return WorkWeek{}, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = parseWeekday(bounds[1]); err != nil {
+				return WorkWeek{}, err
+			}
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			week[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+	if week.IsZero() {
+		return WorkWeek{}, fmt.Errorf("work week %q has no working days", pattern)
+	}
+	return week, nil
+}
+
+func parseWeekday(name string) (time.Weekday, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) >= 3 {
+		if day, ok := weekdayNames[name[:3]]; ok {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown weekday %q", name)
+}
+
+func (w WorkWeek) IsZero() bool {
+	return w == WorkWeek{}
+}
+
+func (w WorkWeek) Includes(date time.Time) bool {
+	return w[date.Weekday()]
+}
+
+// workWeekFor returns the developer's own work week, or the team default.
+func (s *Scheduler) workWeekFor(dev *Developer) WorkWeek {
+	if dev.WorkWeek.IsZero() {
+		return s.workWeek
+	}
+	return dev.WorkWeek
+}
+
+// isHoliday reports whether date is a public holiday for the developer's
+// location. Holidays without a region apply to everyone.
+func (s *Scheduler) isHoliday(dev *Developer, date time.Time) bool {
+	for _, holiday := range s.holidays {
+		if !sameDay(holiday.Date, date) {
+			continue
+		}
+		if holiday.Region == "" || holiday.Region == dev.Location {
+			s.debug("Developer %s is off for %s on %v", dev.Name, holiday.Name, date)
+			return true
+		}
+	}
+	return false
+}
+
+// isWorkingDay reports whether the developer works on date at all, ignoring
+// on-call and leave.
+func (s *Scheduler) isWorkingDay(dev *Developer, date time.Time) bool {
+	return s.workWeekFor(dev).Includes(date) && !s.isHoliday(dev, date)
+}
+
+// isAnyWorkingDay reports whether at least one of devs works on date.
+func (s *Scheduler) isAnyWorkingDay(devs []*Developer, date time.Time) bool {
+	for _, dev := range devs {
+		if s.isWorkingDay(dev, date) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTeamWorkingDay reports whether anyone on the team works on date. With no
+// developers loaded it falls back to the team's default work week.
+func (s *Scheduler) isTeamWorkingDay(date time.Time) bool {
+	if len(s.developers) == 0 {
+		return s.workWeek.Includes(date)
+	}
+	return s.isAnyWorkingDay(s.developers, date)
+}
+
+func (s *Scheduler) addWorkdays(date time.Time, days int) time.Time {
+	result := date
+	for days > 0 {
+		result = result.AddDate(0, 0, 1)
+		if s.isTeamWorkingDay(result) {
+			days--
+		}
+	}
+	return result
+}
+
+func (s *Scheduler) subtractWorkdays(date time.Time, days int) time.Time {
+	result := date
+	for days > 0 {
+		result = result.AddDate(0, 0, -1)
+		if s.isTeamWorkingDay(result) {
+			days--
+		}
+	}
+	return result
+}
+
+// workdaysBetween counts the team working days in the inclusive range
+// [start, end].
+func (s *Scheduler) workdaysBetween(start, end time.Time) int {
+	count := 0
+	for date := dayOf(start); !date.After(end); date = date.AddDate(0, 0, 1) {
+		if s.isTeamWorkingDay(date) {
+			count++
+		}
+	}
+	return count
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHolidays(t *testing.T) {
+	tuesday := monday.AddDate(0, 0, 1)
+	berlin := &Developer{Name: "Dev1", Location: "DE"}
+	boston := &Developer{Name: "Dev2", Location: "US"}
+	tests := []struct {
+		name     string
+		devs     []*Developer
+		holidays []Holiday
+		date     time.Time
+		working  map[string]bool // Per developer
+		team     bool
+	}{
+		{
+			name:    "ordinary weekday",
+			devs:    []*Developer{berlin, boston},
+			date:    tuesday,
+			working: map[string]bool{"Dev1": true, "Dev2": true},
+			team:    true,
+		},
+		{
+			name:     "regional holiday",
+			devs:     []*Developer{berlin, boston},
+			holidays: []Holiday{{Date: tuesday, Name: "Feiertag", Region: "DE"}},
+			date:     tuesday,
+			working:  map[string]bool{"Dev1": false, "Dev2": true},
+			team:     true,
+		},
+		{
+			name:     "regional holiday everyone on the team has",
+			devs:     []*Developer{berlin},
+			holidays: []Holiday{{Date: tuesday, Name: "Feiertag", Region: "DE"}},
+			date:     tuesday,
+			working:  map[string]bool{"Dev1": false},
+		},
+		{
+			name:     "holiday for everyone",
+			devs:     []*Developer{berlin, boston},
+			holidays: []Holiday{{Date: tuesday, Name: "Company day"}},
+			date:     tuesday,
+			working:  map[string]bool{"Dev1": false, "Dev2": false},
+		},
+		{
+			name:     "holiday on another day",
+			devs:     []*Developer{berlin, boston},
+			holidays: []Holiday{{Date: monday, Name: "Company day"}},
+			date:     tuesday,
+			working:  map[string]bool{"Dev1": true, "Dev2": true},
+			team:     true,
+		},
+		{
+			name:    "weekend",
+			devs:    []*Developer{berlin, boston},
+			date:    monday.AddDate(0, 0, -1),
+			working: map[string]bool{"Dev1": false, "Dev2": false},
+		},
+		{
+			name: "no developers falls back to the work week",
+			date: tuesday,
+			team: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScheduler(nil, tt.devs, nil, nil, nil, tt.holidays)
+			s.quiet = true
+			for _, dev := range tt.devs {
+				if got := s.isWorkingDay(dev, tt.date); got != tt.working[dev.Name] {
+					t.Errorf("%s working = %v, want %v", dev.Name, got, tt.working[dev.Name])
+				}
+			}
+			if got := s.isTeamWorkingDay(tt.date); got != tt.team {
+				t.Errorf("team working = %v, want %v", got, tt.team)
+			}
+		})
+	}
+}
+
+func TestAddWorkdaysSkipsHolidays(t *testing.T) {
+	devs := []*Developer{{Name: "Dev1", Location: "DE"}}
+	holidays := []Holiday{
+		{Date: monday.AddDate(0, 0, 1), Name: "Company day"},
+		{Date: monday.AddDate(0, 0, 2), Name: "Feiertag", Region: "DE"},
+		{Date: monday.AddDate(0, 0, 3), Name: "Thanksgiving", Region: "US"},
+	}
+	s := NewScheduler(nil, devs, nil, nil, nil, holidays)
+	s.quiet = true
+	// Tuesday and Wednesday are off, Thursday's holiday is elsewhere
+	if got, want := s.addWorkdays(monday, 2), monday.AddDate(0, 0, 4); !got.Equal(want) {
+		t.Errorf("addWorkdays(monday, 2) = %v, want %v", got, want)
+	}
+}
+
+func TestParseWorkWeek(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []time.Weekday
+		wantErr bool
+	}{
+		{pattern: "Mon-Fri", want: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+		{pattern: "Mon-Thu", want: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday}},
+		{pattern: "Sun-Wed,Fri", want: []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Friday}},
+		{pattern: "Fri-Mon", want: []time.Weekday{time.Friday, time.Saturday, time.Sunday, time.Monday}},
+		{pattern: "tuesday, thursday", want: []time.Weekday{time.Tuesday, time.Thursday}},
+		{pattern: "", wantErr: true},
+		{pattern: "Mon-Funday", wantErr: true},
+		{pattern: "Xy", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := parseWorkWeek(tt.pattern)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseWorkWeek(%q) = %v, want error", tt.pattern, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseWorkWeek(%q): %v", tt.pattern, err)
+			}
+			var want WorkWeek
+			for _, day := range tt.want {
+				want[day] = true
+			}
+			if got != want {
+				t.Errorf("parseWorkWeek(%q) = %v, want %v", tt.pattern, got, want)
+			}
+		})
+	}
+}
+
+func TestDeveloperWorkWeek(t *testing.T) {
+	fourDays, _ := parseWorkWeek("Mon-Thu")
+	devs := []*Developer{
+		{Name: "Dev1", WorkWeek: fourDays},
+		{Name: "Dev2"},
+	}
+	s := NewScheduler(nil, devs, nil, nil, nil, nil)
+	s.quiet = true
+	friday := monday.AddDate(0, 0, 4)
+	if s.isWorkingDay(devs[0], friday) {
+		t.Errorf("Dev1 works Friday with a Mon-Thu week")
+	}
+	if !s.isWorkingDay(devs[1], friday) {
+		t.Errorf("Dev2 does not work Friday with the default week")
+	}
+	if !s.isTeamWorkingDay(friday) {
+		t.Errorf("team does not work Friday although Dev2 does")
+	}
+
+	s.workWeek, _ = parseWorkWeek("Sun-Thu")
+	if s.isWorkingDay(devs[1], friday) {
+		t.Errorf("Dev2 works Friday with a Sun-Thu team week")
+	}
+	if !s.isWorkingDay(devs[1], monday.AddDate(0, 0, -1)) {
+		t.Errorf("Dev2 does not work Sunday with a Sun-Thu team week")
+	}
+}
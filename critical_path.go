@@ -0,0 +1,179 @@
+package main
+
+import "sort"
+
+// CriticalPathTask holds the CPM figures for a single scheduled task. All
+// values are expressed in working days relative to the project start.
+type CriticalPathTask struct {
+	Name           string `json:"name"`
+	Duration       int    `json:"duration"`
+	EarliestStart  int    `json:"earliestStart"`
+	EarliestFinish int    `json:"earliestFinish"`
+	LatestStart    int    `json:"latestStart"`
+	LatestFinish   int    `json:"latestFinish"`
+	TotalSlack     int    `json:"totalSlack"`
+	FreeSlack      int    `json:"freeSlack"`
+	Critical       bool   `json:"critical"`
+}
+
+type CriticalPath struct {
+	Path     []string           `json:"path"`
+	Duration int                `json:"duration"`
+	Tasks    []CriticalPathTask `json:"tasks"`
+}
+
+// analyzeCriticalPath runs a forward and backward pass over the dependency
+// graph of the scheduled tasks, using each task's scheduled length in working
+// days as its duration, and marks the tasks that have no slack as critical.
+func (s *Scheduler) analyzeCriticalPath() *CriticalPath {
+	byName := make(map[string]*Task)
+	for _, task := range s.tasks {
+		if task.StartTime.IsZero() || task.EndTime.IsZero() {
+			continue // Skip unscheduled tasks
+		}
+		byName[task.Name] = task
+	}
+
+	order := s.topologicalOrder(byName)
+	successors := make(map[string][]string)
+	for _, name := range order {
+		for _, depName := range byName[name].Dependencies {
+			if _, ok := byName[depName]; ok {
+				successors[depName] = append(successors[depName], name)
+			}
+		}
+	}
+
+	figures := make(map[string]*CriticalPathTask)
+	projectDuration := 0
+
+	// Forward pass
+	for _, name := range order {
+		task := byName[name]
+		cpt := &CriticalPathTask{
+			Name:     name,
+			Duration: s.workdaysBetween(task.StartTime, task.EndTime),
+		}
+		for _, depName := range task.Dependencies {
+			if dep, ok := figures[depName]; ok {
+				cpt.EarliestStart = max(cpt.EarliestStart, earliestStartAfter(task.linkTo(depName), dep, cpt.Duration))
+			}
+		}
+		cpt.EarliestFinish = cpt.EarliestStart + cpt.Duration
+		if cpt.EarliestFinish > projectDuration {
+			projectDuration = cpt.EarliestFinish
+		}
+		figures[name] = cpt
+	}
+
+	// Backward pass
+	for i := len(order) - 1; i >= 0; i-- {
+		cpt := figures[order[i]]
+		cpt.LatestFinish = projectDuration
+		cpt.FreeSlack = projectDuration - cpt.EarliestFinish
+		for _, succName := range successors[cpt.Name] {
+			succ := figures[succName]
+			link := byName[succName].linkTo(cpt.Name)
+			cpt.LatestFinish = min(cpt.LatestFinish, latestFinishBefore(link, succ, cpt.Duration))
+			cpt.FreeSlack = min(cpt.FreeSlack, succ.EarliestStart-earliestStartAfter(link, cpt, succ.Duration))
+		}
+		cpt.LatestStart = cpt.LatestFinish - cpt.Duration
+		cpt.TotalSlack = cpt.LatestStart - cpt.EarliestStart
+		cpt.Critical = cpt.TotalSlack == 0
+		byName[cpt.Name].IsCritical = cpt.Critical
+	}
+
+	result := &CriticalPath{Path: []string{}, Duration: projectDuration}
+	for _, name := range order {
+		result.Tasks = append(result.Tasks, *figures[name])
+	}
+
+	// Walk the chain of critical tasks from the project start to its end
+	var current *CriticalPathTask
+	for _, name := range order {
+		cpt := figures[name]
+		if cpt.Critical && cpt.EarliestStart == 0 {
+			current = cpt
+			break
+		}
+	}
+	for current != nil {
+		result.Path = append(result.Path, current.Name)
+		var next *CriticalPathTask
+		for _, succName := range successors[current.Name] {
+			succ := figures[succName]
+			link := byName[succName].linkTo(current.Name)
+			if succ.Critical && succ.EarliestStart == earliestStartAfter(link, current, succ.Duration) {
+				next = succ
+				break
+			}
+		}
+		current = next
+	}
+
+	return result
+}
+
+// earliestStartAfter returns the earliest start a link to dep allows for a
+// dependent task of the given duration.
+func earliestStartAfter(link DependencyLink, dep *CriticalPathTask, duration int) int {
+	switch link.Type {
+	case StartToStart:
+		return dep.EarliestStart + link.Lag
+	case FinishToFinish:
+		return dep.EarliestFinish + link.Lag - duration
+	default:
+		return dep.EarliestFinish + link.Lag
+	}
+}
+
+// latestFinishBefore returns the latest a dependency of the given duration
+// can finish without delaying succ through link.
+func latestFinishBefore(link DependencyLink, succ *CriticalPathTask, duration int) int {
+	switch link.Type {
+	case StartToStart:
+		return succ.LatestStart - link.Lag + duration
+	case FinishToFinish:
+		return succ.LatestFinish - link.Lag
+	default:
+		return succ.LatestStart - link.Lag
+	}
+}
+
+// topologicalOrder returns the task names so that every task appears after
+// all of its dependencies. Ties are broken by name to keep output stable.
+func (s *Scheduler) topologicalOrder(byName map[string]*Task) []string {
+	inDegree := make(map[string]int)
+	dependents := make(map[string][]string)
+	for name, task := range byName {
+		inDegree[name] += 0
+		for _, depName := range task.Dependencies {
+			if _, ok := byName[depName]; ok {
+				inDegree[name]++
+				dependents[depName] = append(dependents[depName], name)
+			}
+		}
+	}
+
+	var ready []string
+	for name, degree := range inDegree {
+		if degree == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	var order []string
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+		for _, dependent := range dependents[name] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	return order
+}
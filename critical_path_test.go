@@ -0,0 +1,135 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// monday is a Monday, so working days run without a weekend in between for
+// the first week.
+var monday = time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+
+type cpmTask struct {
+	name     string
+	start    int // Working days after monday
+	duration int
+	deps     []string
+}
+
+func newCPMScheduler(specs []cpmTask) *Scheduler {
+	s := NewScheduler(nil, nil, nil, nil, nil, nil)
+	for _, spec := range specs {
+		task := &Task{Name: spec.name}
+		for _, dep := range spec.deps {
+			link := parseDependency(dep)
+			task.Dependencies = append(task.Dependencies, link.Task)
+			task.Links = append(task.Links, link)
+		}
+		task.StartTime = s.addWorkdays(monday, spec.start)
+		task.EndTime = s.addWorkdays(task.StartTime, spec.duration-1)
+		s.tasks = append(s.tasks, task)
+	}
+	return s
+}
+
+func TestAnalyzeCriticalPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		tasks    []cpmTask
+		duration int
+		path     []string
+		slack    map[string]int
+	}{
+		{
+			name: "single task",
+			tasks: []cpmTask{
+				{name: "A", duration: 3},
+			},
+			duration: 3,
+			path:     []string{"A"},
+			slack:    map[string]int{"A": 0},
+		},
+		{
+			name: "chain with a parallel branch",
+			tasks: []cpmTask{
+				{name: "A", duration: 3},
+				{name: "B", start: 3, duration: 3, deps: []string{"A"}},
+				{name: "C", duration: 2},
+			},
+			duration: 6,
+			path:     []string{"A", "B"},
+			slack:    map[string]int{"A": 0, "B": 0, "C": 4},
+		},
+		{
+			name: "longest of two branches is critical",
+			tasks: []cpmTask{
+				{name: "A", duration: 2},
+				{name: "B", start: 2, duration: 4, deps: []string{"A"}},
+				{name: "C", start: 2, duration: 1, deps: []string{"A"}},
+				{name: "D", start: 6, duration: 1, deps: []string{"B", "C"}},
+			},
+			duration: 7,
+			path:     []string{"A", "B", "D"},
+			slack:    map[string]int{"A": 0, "B": 0, "C": 3, "D": 0},
+		},
+		{
+			name: "finish-to-start with lag",
+			tasks: []cpmTask{
+				{name: "A", duration: 3},
+				{name: "B", start: 5, duration: 3, deps: []string{"A:FS+2"}},
+				{name: "C", duration: 6},
+			},
+			duration: 8,
+			path:     []string{"A", "B"},
+			slack:    map[string]int{"A": 0, "B": 0, "C": 2},
+		},
+		{
+			name: "start-to-start with lag overlaps the dependency",
+			tasks: []cpmTask{
+				{name: "A", duration: 3},
+				{name: "B", start: 1, duration: 3, deps: []string{"A:SS+1"}},
+			},
+			duration: 4,
+			path:     []string{"A", "B"},
+			slack:    map[string]int{"A": 0, "B": 0},
+		},
+		{
+			name: "finish-to-finish with lag",
+			tasks: []cpmTask{
+				{name: "A", duration: 3},
+				{name: "B", start: 3, duration: 2, deps: []string{"A:FF+2"}},
+			},
+			duration: 5,
+			path:     []string{"A", "B"},
+			slack:    map[string]int{"A": 0, "B": 0},
+		},
+		{
+			name: "short start-to-start successor leaves slack",
+			tasks: []cpmTask{
+				{name: "A", duration: 5},
+				{name: "B", start: 1, duration: 2, deps: []string{"A:SS+1"}},
+			},
+			duration: 5,
+			path:     []string{"A"},
+			slack:    map[string]int{"A": 0, "B": 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp := newCPMScheduler(tt.tasks).analyzeCriticalPath()
+			if cp.Duration != tt.duration {
+				t.Errorf("duration = %d, want %d", cp.Duration, tt.duration)
+			}
+			if !reflect.DeepEqual(cp.Path, tt.path) {
+				t.Errorf("path = %v, want %v", cp.Path, tt.path)
+			}
+			for _, task := range cp.Tasks {
+				if want, ok := tt.slack[task.Name]; ok && task.TotalSlack != want {
+					t.Errorf("%s total slack = %d, want %d", task.Name, task.TotalSlack, want)
+				}
+			}
+		})
+	}
+}
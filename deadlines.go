@@ -0,0 +1,147 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// deadlineRiskWindow is how many working days of headroom a task may have
+// before its latest start and still be treated as at risk.
+const deadlineRiskWindow = 10
+
+type DeadlineViolation struct {
+	Task         string `json:"task"`
+	DueDate      string `json:"dueDate"`
+	EndDate      string `json:"endDate"`
+	WorkdaysLate int    `json:"workdaysLate"`
+}
+
+// findDeadlineViolations reports every scheduled task that ends after its due
+// date, ordered from most to least late.
+func (s *Scheduler) findDeadlineViolations() []DeadlineViolation {
+	violations := []DeadlineViolation{}
+	for _, task := range s.tasks {
+		if task.DueDate.IsZero() || task.EndTime.IsZero() {
+			continue
+		}
+		if late := s.workdaysLate(task); late > 0 {
+			violations = append(violations, DeadlineViolation{
+				Task:         task.Name,
+				DueDate:      task.DueDate.Format("2006-01-02"),
+				EndDate:      task.EndTime.Format("2006-01-02"),
+				WorkdaysLate: late,
+			})
+			s.debug("Task %s misses its due date %v by %d working days", task.Name, task.DueDate, late)
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].WorkdaysLate > violations[j].WorkdaysLate
+	})
+	return violations
+}
+
+// workdaysLate returns the number of working days a task finishes after its
+// due date, or 0 if it is on time or has no due date.
+func (s *Scheduler) workdaysLate(task *Task) int {
+	if task.DueDate.IsZero() || !dayOf(task.EndTime).After(task.DueDate) {
+		return 0
+	}
+	return s.workdaysBetween(task.DueDate.AddDate(0, 0, 1), task.EndTime)
+}
+
+// latestStartDates works backwards from each due date through the dependency
+// graph and returns the latest date every constrained task can start and still
+// let itself and all of its dependents finish on time.
+func (s *Scheduler) latestStartDates() map[string]time.Time {
+	dependents := make(map[string][]*Task)
+	for _, task := range s.tasks {
+		for _, depName := range task.Dependencies {
+			dependents[depName] = append(dependents[depName], task)
+		}
+	}
+
+	latestStarts := make(map[string]time.Time)
+	visited := make(map[string]bool)
+
+	var resolve func(task *Task) time.Time
+	resolve = func(task *Task) time.Time {
+		if visited[task.Name] {
+			return latestStarts[task.Name]
+		}
+		visited[task.Name] = true
+
+		// The latest this task may finish is its own due date or the latest
+		// its dependents' links allow, whichever comes first.
+		latestFinish := task.DueDate
+		for _, dependent := range dependents[task.Name] {
+			dependentStart := resolve(dependent)
+			if dependentStart.IsZero() {
+				continue
+			}
+			link := dependent.linkTo(task.Name)
+			var finish time.Time
+			switch link.Type {
+			case StartToStart:
+				finish = s.shiftWorkdays(dependentStart, s.estimateWorkdays(task)-link.Lag)
+			case FinishToFinish:
+				finish = s.shiftWorkdays(dependentStart, s.estimateWorkdays(dependent)-link.Lag)
+			default:
+				finish = s.shiftWorkdays(dependentStart, -link.Lag)
+			}
+			if latestFinish.IsZero() || finish.Before(latestFinish) {
+				latestFinish = finish
+			}
+		}
+		if latestFinish.IsZero() {
+			return time.Time{}
+		}
+
+		latestStart := s.subtractWorkdays(latestFinish, s.estimateWorkdays(task))
+		latestStarts[task.Name] = latestStart
+		return latestStart
+	}
+
+	for _, task := range s.tasks {
+		resolve(task)
+	}
+	return latestStarts
+}
+
+// estimateWorkdays gives a rough duration for a task assuming every parallel
+// slot is filled at full availability.
+func (s *Scheduler) estimateWorkdays(task *Task) int {
+	parallel := math.Max(float64(task.ParallelFactor), 1)
+	return int(math.Ceil(task.Effort / parallel))
+}
+
+// sortTasksByDeadlineRisk orders tasks whose latest start falls within the
+// risk window first, earliest latest start first, and leaves the remaining
+// tasks in priority order behind them.
+func (s *Scheduler) sortTasksByDeadlineRisk(startDate time.Time) {
+	latestStarts := s.latestStartDates()
+	riskCutoff := s.addWorkdays(startDate, deadlineRiskWindow)
+
+	atRisk := func(task *Task) bool {
+		latestStart, ok := latestStarts[task.Name]
+		return ok && !latestStart.After(riskCutoff)
+	}
+
+	sort.SliceStable(s.tasks, func(i, j int) bool {
+		iRisk, jRisk := atRisk(s.tasks[i]), atRisk(s.tasks[j])
+		if iRisk != jRisk {
+			return iRisk
+		}
+		if iRisk && !latestStarts[s.tasks[i].Name].Equal(latestStarts[s.tasks[j].Name]) {
+			return latestStarts[s.tasks[i].Name].Before(latestStarts[s.tasks[j].Name])
+		}
+		return s.tasks[i].Priority < s.tasks[j].Priority
+	})
+
+	for _, task := range s.tasks {
+		if atRisk(task) {
+			s.debug("Task %s is at risk of missing its deadline, latest start %v", task.Name, latestStarts[task.Name])
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWorkdaysLate(t *testing.T) {
+	friday := monday.AddDate(0, 0, 4)
+	tests := []struct {
+		name string
+		due  time.Time
+		end  time.Time
+		want int
+	}{
+		{name: "no due date", end: friday},
+		{name: "early", due: friday, end: monday.Add(17 * time.Hour)},
+		{name: "on the due date", due: friday, end: friday.Add(17 * time.Hour)},
+		{name: "one day late", due: monday, end: monday.AddDate(0, 0, 1).Add(13 * time.Hour), want: 1},
+		{name: "over a weekend", due: friday, end: friday.AddDate(0, 0, 3).Add(10 * time.Hour), want: 1},
+		{name: "a week late", due: monday, end: monday.AddDate(0, 0, 7), want: 5},
+	}
+
+	s := NewScheduler(nil, nil, nil, nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := &Task{Name: "A", DueDate: tt.due, EndTime: tt.end}
+			if got := s.workdaysLate(task); got != tt.want {
+				t.Errorf("workdaysLate = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindDeadlineViolations(t *testing.T) {
+	tasks := []*Task{
+		{Name: "OnTime", DueDate: monday.AddDate(0, 0, 4), EndTime: monday.AddDate(0, 0, 2)},
+		{Name: "Late", DueDate: monday, EndTime: monday.AddDate(0, 0, 1)},
+		{Name: "NoDueDate", EndTime: monday.AddDate(0, 0, 30)},
+		{Name: "Unscheduled", DueDate: monday},
+		{Name: "VeryLate", DueDate: monday, EndTime: monday.AddDate(0, 0, 9)},
+	}
+	s := NewScheduler(tasks, nil, nil, nil, nil, nil)
+	s.quiet = true
+
+	want := []DeadlineViolation{
+		{Task: "VeryLate", DueDate: "2026-10-12", EndDate: "2026-10-21", WorkdaysLate: 7},
+		{Task: "Late", DueDate: "2026-10-12", EndDate: "2026-10-13", WorkdaysLate: 1},
+	}
+	if got := s.findDeadlineViolations(); !reflect.DeepEqual(got, want) {
+		t.Errorf("findDeadlineViolations = %+v, want %+v", got, want)
+	}
+}
+
+func TestSortTasksByDeadlineRisk(t *testing.T) {
+	tasks := []*Task{
+		{Name: "Relaxed", Priority: 1, Effort: 1, DueDate: monday.AddDate(0, 3, 0)},
+		{Name: "Urgent", Priority: 3, Effort: 5, DueDate: monday.AddDate(0, 0, 7)},
+		{Name: "Free", Priority: 2, Effort: 1},
+		{Name: "Soon", Priority: 4, Effort: 1, DueDate: monday.AddDate(0, 0, 14)},
+	}
+	s := NewScheduler(tasks, nil, nil, nil, nil, nil)
+	s.quiet = true
+	s.sortTasksByDeadlineRisk(monday)
+
+	var got []string
+	for _, task := range s.tasks {
+		got = append(got, task.Name)
+	}
+	if want := []string{"Urgent", "Soon", "Relaxed", "Free"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LinkType is how a task depends on one of its dependencies.
+type LinkType string
+
+const (
+	FinishToStart  LinkType = "FS" // Start after the dependency finishes
+	StartToStart   LinkType = "SS" // Start after the dependency starts
+	FinishToFinish LinkType = "FF" // Finish after the dependency finishes
+)
+
+// DependencyLink is a typed dependency with a lag in working days. A negative
+// lag lets the dependent lead its dependency.
+type DependencyLink struct {
+	Task string   `json:"task"`
+	Type LinkType `json:"type"`
+	Lag  int      `json:"lag,omitempty"`
+}
+
+func (l DependencyLink) String() string {
+	if l.Lag == 0 {
+		return string(l.Type)
+	}
+	return fmt.Sprintf("%s%+d", l.Type, l.Lag)
+}
+
+var linkSpecPattern = regexp.MustCompile(`^(?i)(FS|SS|FF)([+-]\d+)?$`)
+
+// parseLinkSpec parses a link type with optional lag such as "FS", "SS+3" or
+// "FF-1". An empty spec is a plain finish-to-start link.
+func parseLinkSpec(spec string) (LinkType, int, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return FinishToStart, 0, nil
+	}
+	match := linkSpecPattern.FindStringSubmatch(spec)
+	if match == nil {
+		return "", 0, fmt.Errorf("invalid dependency link %q, expected FS, SS or FF with an optional lag like FS+2", spec)
+	}
+	lag := 0
+	if match[2] != "" {
+		lag, _ = strconv.Atoi(match[2])
+	}
+	return LinkType(strings.ToUpper(match[1])), lag, nil
+}
+
+// parseDependency splits a dependency entry of the form "Name" or
+// "Name:SS+3". A suffix that is not a valid link spec is treated as part of
+// the task name, so names containing colons still work.
+func parseDependency(entry string) DependencyLink {
+	entry = strings.TrimSpace(entry)
+	if i := strings.LastIndex(entry, ":"); i >= 0 {
+		if linkType, lag, err := parseLinkSpec(entry[i+1:]); err == nil && strings.TrimSpace(entry[i+1:]) != "" {
+			return DependencyLink{Task: strings.TrimSpace(entry[:i]), Type: linkType, Lag: lag}
+		}
+	}
+	return DependencyLink{Task: entry, Type: FinishToStart}
+}
+
+// linkTo returns how the task depends on depName, finish-to-start by default.
+func (t *Task) linkTo(depName string) DependencyLink {
+	for _, link := range t.Links {
+		if link.Task == depName {
+			return link
+		}
+	}
+	return DependencyLink{Task: depName, Type: FinishToStart}
+}
+
+// shiftWorkdays moves date by n team working days, backwards when n < 0.
+func (s *Scheduler) shiftWorkdays(date time.Time, n int) time.Time {
+	if n < 0 {
+		return s.subtractWorkdays(date, -n)
+	}
+	return s.addWorkdays(date, n)
+}
+
+// canStartAfter reports whether a link allows its dependent to start on date.
+// Finish-to-finish links never hold back the start.
+func (s *Scheduler) canStartAfter(link DependencyLink, dep *Task, date time.Time) bool {
+	switch link.Type {
+	case StartToStart:
+		return !dep.StartTime.IsZero() && !date.Before(dayOf(s.shiftWorkdays(dep.StartTime, link.Lag)))
+	case FinishToFinish:
+		return true
+	default:
+		return dep.IsCompleted && !date.Before(dayOf(s.shiftWorkdays(dep.EndTime, link.Lag)))
+	}
+}
+
+// canFinishAfter reports whether a task's finish-to-finish links allow it to
+// finish on date.
+func (s *Scheduler) canFinishAfter(task *Task, date time.Time) bool {
+	for _, link := range task.Links {
+		if link.Type != FinishToFinish {
+			continue
+		}
+		dep := s.findTask(link.Task)
+		if dep == nil {
+			continue
+		}
+		if !dep.IsCompleted || date.Before(dayOf(s.shiftWorkdays(dep.EndTime, link.Lag))) {
+			s.debug("Task %s waits for %s to finish (%s)", task.Name, dep.Name, link)
+			return false
+		}
+	}
+	return true
+}
+
+// finishNotBefore returns the earliest moment a task's finish-to-finish links
+// let it finish, zero when it has none.
+func (s *Scheduler) finishNotBefore(task *Task) time.Time {
+	var earliest time.Time
+	for _, link := range task.Links {
+		if link.Type != FinishToFinish {
+			continue
+		}
+		if dep := s.findTask(link.Task); dep != nil && dep.IsCompleted {
+			if at := s.shiftWorkdays(dep.EndTime, link.Lag); at.After(earliest) {
+				earliest = at
+			}
+		}
+	}
+	return earliest
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+const defaultForecastRuns = 200
+
+type Percentiles struct {
+	P50 string `json:"p50"`
+	P80 string `json:"p80"`
+	P95 string `json:"p95"`
+}
+
+type TaskForecast struct {
+	Task string `json:"task"`
+	Percentiles
+	DueDate           string   `json:"dueDate,omitempty"`
+	OnTimeProbability *float64 `json:"onTimeProbability,omitempty"`
+}
+
+type Forecast struct {
+	Runs    int            `json:"runs"`
+	Project Percentiles    `json:"project"`
+	Tasks   []TaskForecast `json:"tasks"`
+}
+
+// Forecast runs the greedy strategy runs times on independent copies of the
+// scheduler, sampling the effort of every task with a three-point estimate
+// from its PERT distribution, and reports finish date percentiles. The
+// configured strategy is not used: optimizing every run would multiply the
+// cost of a forecast by the annealing iterations.
+func (s *Scheduler) Forecast(startDate time.Time, runs int, seed int64) *Forecast {
+	rng := rand.New(rand.NewSource(seed))
+
+	taskEnds := make(map[string][]time.Time)
+	onTime := make(map[string]int)
+	var projectEnds []time.Time
+
+	for run := 0; run < runs; run++ {
+		trial := s.clone()
+		for _, task := range trial.tasks {
+			if !task.Estimate.IsZero() {
+				task.Effort = samplePERT(rng, task.Estimate)
+			}
+		}
+		greedyStrategy{}.Schedule(trial, startDate)
+
+		var projectEnd time.Time
+		for _, task := range trial.tasks {
+			if !task.IsCompleted {
+				continue
+			}
+			taskEnds[task.Name] = append(taskEnds[task.Name], task.EndTime)
+			if !task.DueDate.IsZero() && trial.workdaysLate(task) == 0 {
+				onTime[task.Name]++
+			}
+			if task.EndTime.After(projectEnd) {
+				projectEnd = task.EndTime
+			}
+		}
+		if !projectEnd.IsZero() {
+			projectEnds = append(projectEnds, projectEnd)
+		}
+	}
+
+	forecast := &Forecast{
+		Runs:    runs,
+		Project: percentilesOf(projectEnds),
+		Tasks:   []TaskForecast{},
+	}
+	for _, task := range s.tasks {
+		ends, ok := taskEnds[task.Name]
+		if !ok {
+			continue // Never scheduled in any run
+		}
+		tf := TaskForecast{
+			Task:        task.Name,
+			Percentiles: percentilesOf(ends),
+		}
+		if !task.DueDate.IsZero() {
+			tf.DueDate = task.DueDate.Format("2006-01-02")
+			probability := float64(onTime[task.Name]) / float64(runs)
+			tf.OnTimeProbability = &probability
+		}
+		forecast.Tasks = append(forecast.Tasks, tf)
+	}
+	return forecast
+}
+
+func percentilesOf(dates []time.Time) Percentiles {
+	if len(dates) == 0 {
+		return Percentiles{}
+	}
+	sorted := append([]time.Time(nil), dates...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	at := func(p float64) string {
+		i := int(math.Ceil(p*float64(len(sorted)))) - 1
+		return sorted[max(i, 0)].Format("2006-01-02")
+	}
+	return Percentiles{P50: at(0.5), P80: at(0.8), P95: at(0.95)}
+}
+
+// samplePERT draws an effort from the Beta-PERT distribution of an estimate.
+func samplePERT(rng *rand.Rand, e Estimate) float64 {
+	spread := e.Pessimistic - e.Optimistic
+	if spread <= 0 {
+		return e.Likely
+	}
+	alpha := 1 + 4*(e.Likely-e.Optimistic)/spread
+	beta := 1 + 4*(e.Pessimistic-e.Likely)/spread
+
+	x := sampleGamma(rng, alpha)
+	y := sampleGamma(rng, beta)
+	return e.Optimistic + spread*x/(x+y)
+}
+
+// sampleGamma draws from Gamma(shape, 1) using Marsaglia and Tsang's method.
+// Every PERT shape parameter is at least 1, which the method requires.
+func sampleGamma(rng *rand.Rand, shape float64) float64 {
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
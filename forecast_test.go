@@ -0,0 +1,60 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSamplePERT(t *testing.T) {
+	tests := []struct {
+		name     string
+		estimate Estimate
+	}{
+		{"symmetric", Estimate{Optimistic: 2, Likely: 4, Pessimistic: 6}},
+		{"skewed right", Estimate{Optimistic: 1, Likely: 2, Pessimistic: 10}},
+		{"likely at optimistic", Estimate{Optimistic: 3, Likely: 3, Pessimistic: 5}},
+		{"likely at pessimistic", Estimate{Optimistic: 3, Likely: 5, Pessimistic: 5}},
+		{"fixed", Estimate{Optimistic: 4, Likely: 4, Pessimistic: 4}},
+	}
+
+	const samples = 20000
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			sum := 0.0
+			for i := 0; i < samples; i++ {
+				v := samplePERT(rng, tt.estimate)
+				if v < tt.estimate.Optimistic || v > tt.estimate.Pessimistic {
+					t.Fatalf("sample %v outside [%v, %v]", v, tt.estimate.Optimistic, tt.estimate.Pessimistic)
+				}
+				sum += v
+			}
+			// The sample mean converges on the PERT mean
+			mean := sum / samples
+			if tolerance := 0.02 * (tt.estimate.Pessimistic - tt.estimate.Optimistic); math.Abs(mean-tt.estimate.Mean()) > tolerance {
+				t.Errorf("sample mean = %.3f, want %.3f", mean, tt.estimate.Mean())
+			}
+		})
+	}
+}
+
+func TestForecastRunsGreedy(t *testing.T) {
+	forecast := func(strategy ScheduleStrategy) *Forecast {
+		s := newWIPScheduler(1, 0, 2, 3)
+		for _, task := range s.tasks {
+			task.Estimate = Estimate{Optimistic: task.Effort - 1, Likely: task.Effort, Pessimistic: task.Effort + 2}
+		}
+		s.strategy = strategy
+		return s.Forecast(monday, 20, 1)
+	}
+
+	optimize, err := newScheduleStrategy("optimize")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := forecast(optimize), forecast(greedyStrategy{}); !reflect.DeepEqual(got, want) {
+		t.Errorf("forecast with optimize = %+v, want the greedy forecast %+v", got, want)
+	}
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// standardHoursPerDay is the length of the working day effort is measured
+	// in: one day of effort is this many hours of work.
+	standardHoursPerDay = 8.0
+
+	// workdayStart is when every developer's working day begins.
+	workdayStart = 9 * time.Hour
+
+	// timestampLayout is how hour-resolution times are written in CSV files.
+	timestampLayout = "2006-01-02 15:04"
+)
+
+// parseTimestamp parses a date, or a date and time of day written as
+// "2006-01-02 15:04" or "2006-01-02T15:04".
+func parseTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"2006-01-02", timestampLayout, "2006-01-02T15:04"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("expected 2006-01-02 or %s, got %q", timestampLayout, value)
+}
+
+// parseEffortValue parses an effort in days, or in hours with an "h" suffix,
+// e.g. "3h", and returns it in days.
+func parseEffortValue(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if hours, ok := strings.CutSuffix(value, "h"); ok {
+		effort, err := strconv.ParseFloat(strings.TrimSpace(hours), 64)
+		if err != nil {
+			return 0, err
+		}
+		return effort / standardHoursPerDay, nil
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// roundToHour rounds an effort in days to whole hours of work.
+func roundToHour(effort float64) float64 {
+	return math.Round(effort*standardHoursPerDay) / standardHoursPerDay
+}
+
+// hasClock reports whether t carries a time of day rather than just a date.
+func hasClock(t time.Time) bool {
+	return t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0
+}
+
+// dayOf truncates t to midnight of its day.
+func dayOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// periodEnd returns when a period ending at end is over: end itself when it
+// has a time of day, otherwise the end of that day.
+func periodEnd(end time.Time) time.Time {
+	if hasClock(end) {
+		return end
+	}
+	return end.AddDate(0, 0, 1)
+}
+
+// hoursPerDay returns how many hours a developer works on a working day.
+func (s *Scheduler) hoursPerDay(dev *Developer) float64 {
+	if dev.HoursPerDay <= 0 {
+		return standardHoursPerDay
+	}
+	return dev.HoursPerDay
+}
+
+// workingDay returns when a developer's working day on date starts and ends.
+func (s *Scheduler) workingDay(dev *Developer, date time.Time) (time.Time, time.Time) {
+	start := dayOf(date).Add(workdayStart)
+	return start, start.Add(time.Duration(s.hoursPerDay(dev) * float64(time.Hour)))
+}
+
+// coverage returns the share of a developer's working day on date that falls
+// within [start, end]. Bounds without a time of day cover whole days, the end
+// day included.
+func (s *Scheduler) coverage(dev *Developer, date, start, end time.Time) float64 {
+	if !hasClock(start) && !hasClock(end) {
+		if date.Before(start) || date.After(end) {
+			return 0
+		}
+		return 1
+	}
+	end = periodEnd(end)
+	dayStart, dayEnd := s.workingDay(dev, date)
+	if start.Before(dayStart) {
+		start = dayStart
+	}
+	if end.After(dayEnd) {
+		end = dayEnd
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start).Hours() / s.hoursPerDay(dev)
+}
+
+// timeRange is a span of working time within a day.
+type timeRange struct {
+	start, end time.Time
+}
+
+// workingHours returns the spans of date a developer is at work: their
+// working day less any leave, or on-call duty leaving no capacity, taken by
+// the hour.
+func (s *Scheduler) workingHours(dev *Developer, date time.Time) []timeRange {
+	dayStart, dayEnd := s.workingDay(dev, date)
+	var away []timeRange
+	for _, leave := range s.leaves {
+		if leave.DevName == dev.Name && leave.Fraction >= 1 {
+			away = append(away, timeRange{leave.StartTime, leave.EndTime})
+		}
+	}
+	for _, oncall := range s.oncalls {
+		if oncall.DevName == dev.Name && s.onCallRowCapacity(dev, oncall) <= 0 {
+			away = append(away, timeRange{oncall.StartTime, oncall.EndTime})
+		}
+	}
+
+	ranges := []timeRange{{dayStart, dayEnd}}
+	for _, a := range away {
+		if !hasClock(a.start) && !hasClock(a.end) {
+			continue // Whole days off are covered by the capacity
+		}
+		end := periodEnd(a.end)
+		var left []timeRange
+		for _, r := range ranges {
+			if !a.start.Before(r.end) || !end.After(r.start) {
+				left = append(left, r)
+				continue
+			}
+			if a.start.After(r.start) {
+				left = append(left, timeRange{r.start, a.start})
+			}
+			if end.Before(r.end) {
+				left = append(left, timeRange{end, r.end})
+			}
+		}
+		ranges = left
+	}
+	return ranges
+}
+
+// hoursFrom counts the working hours in ranges from a moment on.
+func hoursFrom(ranges []timeRange, from time.Time) float64 {
+	hours := 0.0
+	for _, r := range ranges {
+		if r.start.Before(from) {
+			r.start = from
+		}
+		if r.end.After(r.start) {
+			hours += r.end.Sub(r.start).Hours()
+		}
+	}
+	return hours
+}
+
+// placeHours lays hours of work into ranges from a moment on and returns when
+// the work starts and ends.
+func placeHours(ranges []timeRange, from time.Time, hours float64) (time.Time, time.Time) {
+	var start, end time.Time
+	for _, r := range ranges {
+		if r.start.Before(from) {
+			r.start = from
+		}
+		if !r.end.After(r.start) {
+			continue
+		}
+		if start.IsZero() {
+			start = r.start
+		}
+		available := r.end.Sub(r.start).Hours()
+		if hours <= available {
+			end = r.start.Add(time.Duration(hours * float64(time.Hour)))
+			break
+		}
+		hours -= available
+		end = r.end
+	}
+	return start, end.Round(time.Minute)
+}
+
+// booking is how much of a developer's working day has been handed out.
+type booking struct {
+	until time.Time // Work on the day so far ends here
+	hours float64   // Hours of project work handed out on the day
+}
+
+// booked returns how much of a developer's working day on date is taken.
+func (s *Scheduler) booked(dev *Developer, date time.Time) booking {
+	if b, ok := s.bookings[dev.Name]; ok && sameDay(b.until, date) {
+		return b
+	}
+	start, _ := s.workingDay(dev, date)
+	return booking{until: start}
+}
+
+// hoursLeft returns the project hours a developer has left on date.
+func (s *Scheduler) hoursLeft(dev *Developer, date time.Time) float64 {
+	b := s.booked(dev, date)
+	return math.Min(s.projectHours(dev, date)-b.hours, hoursFrom(s.workingHours(dev, date), b.until))
+}
+
+// rebook recounts a developer's booking on date from the ledgers, after work
+// recorded for them has been taken back.
+func (s *Scheduler) rebook(dev *Developer, date time.Time) {
+	start, _ := s.workingDay(dev, date)
+	b := booking{until: start}
+	for _, task := range s.tasks {
+		for _, entry := range task.Ledger {
+			if entry.Developer != dev.Name || !sameDay(entry.Date, date) {
+				continue
+			}
+			b.hours += entry.Hours
+			if entry.End.After(b.until) {
+				b.until = entry.End
+			}
+		}
+	}
+	s.bookings[dev.Name] = b
+}
+
+// projectHours returns the hours a developer puts into project work on date:
+// their working hours scaled by availability, on-call duty and leave.
+func (s *Scheduler) projectHours(dev *Developer, date time.Time) float64 {
+	return s.hoursPerDay(dev) * s.availability(dev, date) * s.onCallCapacity(dev, date) * s.leaveCapacity(dev, date)
+}
+
+// hourlyOutput returns the effort a developer delivers on a task per hour of
+// work on date.
+func (s *Scheduler) hourlyOutput(dev *Developer, task *Task, date time.Time) float64 {
+	return s.proficiency(dev, task.TaskType) * s.rampUpFactor(dev, date) / standardHoursPerDay
+}
+
+// startNotBefore returns the earliest moment on date a task may be worked on,
+// taking into account the time of day its earliest start and its start and
+// finish-to-start dependencies allow.
+func (s *Scheduler) startNotBefore(task *Task, date time.Time) time.Time {
+	earliest := dayOf(date)
+	if task.EarliestStart.After(earliest) {
+		earliest = task.EarliestStart
+	}
+	for _, depName := range task.Dependencies {
+		link := task.linkTo(depName)
+		dep := s.findTask(link.Task)
+		if dep == nil {
+			continue
+		}
+		var at time.Time
+		switch link.Type {
+		case StartToStart:
+			at = s.shiftWorkdays(dep.StartTime, link.Lag)
+		case FinishToStart:
+			if dep.IsCompleted {
+				at = s.shiftWorkdays(dep.EndTime, link.Lag)
+			}
+		}
+		if at.After(earliest) {
+			earliest = at
+		}
+	}
+	return earliest
+}
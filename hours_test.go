@@ -0,0 +1,173 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseEffortValue(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{value: "3", want: 3},
+		{value: "1.5", want: 1.5},
+		{value: "4h", want: 0.5},
+		{value: " 12h ", want: 1.5},
+		{value: "2 h", want: 0.25},
+		{value: "h", wantErr: true},
+		{value: "abc", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseEffortValue(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseEffortValue(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseEffortValue(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseEffort(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{value: "5", want: 5},
+		{value: "4h", want: 0.5},
+		{value: "1/2/9", want: 3},
+		{value: "8h/2/3", want: 2},
+		{value: "abc", wantErr: true},
+		{value: "-1", wantErr: true},
+		{value: "1/2", wantErr: true},
+		{value: "3/2/1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, _, err := parseEffort(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseEffort(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseEffort(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+// at returns a time on monday.
+func at(hour, minute int) time.Time {
+	return monday.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func TestPlaceHours(t *testing.T) {
+	day := []timeRange{{at(9, 0), at(17, 0)}}
+	split := []timeRange{{at(9, 0), at(12, 0)}, {at(13, 0), at(17, 0)}}
+	tests := []struct {
+		name       string
+		ranges     []timeRange
+		from       time.Time
+		hours      float64
+		start, end time.Time
+	}{
+		{name: "from the start of the day", ranges: day, from: monday, hours: 3, start: at(9, 0), end: at(12, 0)},
+		{name: "after earlier work", ranges: day, from: at(11, 0), hours: 2.5, start: at(11, 0), end: at(13, 30)},
+		{name: "spans a gap", ranges: split, from: at(10, 0), hours: 4, start: at(10, 0), end: at(15, 0)},
+		{name: "starts after a gap", ranges: split, from: at(12, 0), hours: 1, start: at(13, 0), end: at(14, 0)},
+		{name: "fills the day", ranges: split, from: monday, hours: 7, start: at(9, 0), end: at(17, 0)},
+		{name: "rounds to the minute", ranges: day, from: monday, hours: 1.0 / 3, start: at(9, 0), end: at(9, 20)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := placeHours(tt.ranges, tt.from, tt.hours)
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Errorf("placeHours = %v-%v, want %v-%v", start.Format("15:04"), end.Format("15:04"), tt.start.Format("15:04"), tt.end.Format("15:04"))
+			}
+			if got := hoursFrom(tt.ranges, tt.from); got < tt.hours {
+				t.Errorf("hoursFrom = %v, fewer than the %v hours placed", got, tt.hours)
+			}
+		})
+	}
+}
+
+func TestWorkingHours(t *testing.T) {
+	none := 0.0
+	half := 0.5
+	tests := []struct {
+		name    string
+		dev     *Developer
+		leaves  []Leave
+		oncalls []OnCall
+		want    []timeRange
+	}{
+		{
+			name: "standard day",
+			dev:  &Developer{Name: "Dev1"},
+			want: []timeRange{{at(9, 0), at(17, 0)}},
+		},
+		{
+			name: "short day",
+			dev:  &Developer{Name: "Dev1", HoursPerDay: 6},
+			want: []timeRange{{at(9, 0), at(15, 0)}},
+		},
+		{
+			name:   "leave by the hour",
+			dev:    &Developer{Name: "Dev1"},
+			leaves: []Leave{{DevName: "Dev1", StartTime: at(12, 0), EndTime: at(14, 0), Fraction: 1}},
+			want:   []timeRange{{at(9, 0), at(12, 0)}, {at(14, 0), at(17, 0)}},
+		},
+		{
+			name:   "leave from the morning on",
+			dev:    &Developer{Name: "Dev1"},
+			leaves: []Leave{{DevName: "Dev1", StartTime: at(8, 0), EndTime: at(10, 30), Fraction: 1}},
+			want:   []timeRange{{at(10, 30), at(17, 0)}},
+		},
+		{
+			name:   "someone else's leave",
+			dev:    &Developer{Name: "Dev1"},
+			leaves: []Leave{{DevName: "Dev2", StartTime: at(12, 0), EndTime: at(14, 0), Fraction: 1}},
+			want:   []timeRange{{at(9, 0), at(17, 0)}},
+		},
+		{
+			name:   "whole days off are left to the capacity",
+			dev:    &Developer{Name: "Dev1"},
+			leaves: []Leave{{DevName: "Dev1", StartTime: monday, EndTime: monday, Fraction: 1}},
+			want:   []timeRange{{at(9, 0), at(17, 0)}},
+		},
+		{
+			name:   "part-time leave is left to the capacity",
+			dev:    &Developer{Name: "Dev1"},
+			leaves: []Leave{{DevName: "Dev1", StartTime: at(12, 0), EndTime: at(14, 0), Fraction: 0.5}},
+			want:   []timeRange{{at(9, 0), at(17, 0)}},
+		},
+		{
+			name:    "on call with no capacity",
+			dev:     &Developer{Name: "Dev1"},
+			oncalls: []OnCall{{DevName: "Dev1", StartTime: at(15, 0), EndTime: at(18, 0), Capacity: &none}},
+			want:    []timeRange{{at(9, 0), at(15, 0)}},
+		},
+		{
+			name:    "on call with capacity left",
+			dev:     &Developer{Name: "Dev1"},
+			oncalls: []OnCall{{DevName: "Dev1", StartTime: at(15, 0), EndTime: at(18, 0), Capacity: &half}},
+			want:    []timeRange{{at(9, 0), at(17, 0)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScheduler(nil, []*Developer{tt.dev}, nil, tt.oncalls, tt.leaves, nil)
+			if got := s.workingHours(tt.dev, monday); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("workingHours = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
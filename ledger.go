@@ -0,0 +1,213 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// releaseAbsenceWorkdays is how many working days in a row a developer
+	// must be unavailable before they are released from a task in progress.
+	// Shorter absences just slow the task down.
+	releaseAbsenceWorkdays = 5
+
+	// effortEpsilon absorbs floating point error in remaining effort.
+	effortEpsilon = 1e-9
+)
+
+// EffortEntry is the effort one developer delivered on a task on one day, and
+// when during the day they worked on it.
+type EffortEntry struct {
+	Date      time.Time
+	Developer string
+	Effort    float64
+	Start     time.Time
+	End       time.Time
+	Hours     float64 // Hours of work, less than End-Start when leave falls between
+}
+
+// deliveredEffort sums the effort recorded in the task's ledger, optionally
+// only for one developer.
+func (t *Task) deliveredEffort(devName string) float64 {
+	delivered := 0.0
+	for _, entry := range t.Ledger {
+		if devName == "" || entry.Developer == devName {
+			delivered += entry.Effort
+		}
+	}
+	return delivered
+}
+
+// remainingEffort is the effort still to be delivered on the task.
+func (t *Task) remainingEffort() float64 {
+	return t.Effort - t.CompletedEffort - t.deliveredEffort("")
+}
+
+// recordProgress adds the effort each assigned developer delivers on date to
+// the task's ledger, stopping once the task's effort is used up. Each
+// developer's working day is handed out in turn, so a developer who finishes
+// one task part way through the day picks up the next one where they left off.
+func (s *Scheduler) recordProgress(task *Task, date time.Time) {
+	notBefore := s.startNotBefore(task, date)
+	for _, dev := range task.AssignedDevs {
+		if task.remainingEffort() <= effortEpsilon {
+			return
+		}
+		s.recordWork(task, dev, date, notBefore, time.Time{})
+	}
+}
+
+// recordWork adds the effort one developer delivers on a task on date to its
+// ledger, working no earlier than notBefore and, unless zero, stopping at
+// until.
+func (s *Scheduler) recordWork(task *Task, dev *Developer, date, notBefore, until time.Time) {
+	remaining := task.remainingEffort()
+	if !s.isWorkingDay(dev, date) || task.workedOn(dev.Name, date) {
+		return
+	}
+	rate := s.hourlyOutput(dev, task, date)
+	b := s.booked(dev, date)
+	from := b.until
+	if notBefore.After(from) {
+		from = notBefore
+	}
+	ranges := s.workingHours(dev, date)
+	projectHours := s.projectHours(dev, date)
+	hours := math.Min(projectHours*s.dailyShare(dev, date), projectHours-b.hours)
+	hours = math.Min(hours, hoursFrom(ranges, from))
+	if !until.IsZero() {
+		hours = math.Min(hours, hoursFrom(ranges, from)-hoursFrom(ranges, until))
+	}
+	if hours <= 0 || rate <= 0 {
+		return
+	}
+	effort := hours * rate
+	if effort > remaining {
+		effort = remaining
+		hours = remaining / rate
+	}
+
+	start, end := placeHours(ranges, from, hours)
+	if a := task.openAssignment(dev.Name); a != nil && a.Effort == 0 && task.effortSince(dev.Name, a.Start) == 0 {
+		a.Start = start // The first hour of work, not the day they joined
+	}
+	if task.CompletedEffort == 0 && len(task.Ledger) == 0 {
+		task.StartTime = start
+	}
+	task.Ledger = append(task.Ledger, EffortEntry{
+		Date:      date,
+		Developer: dev.Name,
+		Effort:    effort,
+		Start:     start,
+		End:       end,
+		Hours:     hours,
+	})
+	s.bookings[dev.Name] = booking{until: end, hours: b.hours + hours}
+}
+
+// workedOn reports whether the ledger has an entry for a developer on date.
+func (t *Task) workedOn(devName string, date time.Time) bool {
+	for _, entry := range t.Ledger {
+		if entry.Developer == devName && sameDay(entry.Date, date) {
+			return true
+		}
+	}
+	return false
+}
+
+// finishedAt returns when the last recorded work on the task ended.
+func (t *Task) finishedAt() time.Time {
+	var end time.Time
+	for _, entry := range t.Ledger {
+		if entry.End.After(end) {
+			end = entry.End
+		}
+	}
+	return end
+}
+
+// releaseAbsentDevs takes developers off a task in progress when they are
+// about to be away for at least releaseAbsenceWorkdays, or in split mode for
+// any interruption, so the slot can be filled by someone else in the
+// meantime.
+func (s *Scheduler) releaseAbsentDevs(task *Task, date time.Time) {
+	if task.remainingEffort() <= effortEpsilon {
+		return
+	}
+	for _, dev := range append([]*Developer{}, task.AssignedDevs...) {
+		if (s.splitTasks && s.isInterrupted(dev, date)) || s.absentWorkdays(dev, date) >= releaseAbsenceWorkdays {
+			s.debug("Releasing developer %s from task %s while away from %v", dev.Name, task.Name, date)
+			s.takeOffTask(task, dev, date, "")
+		}
+	}
+}
+
+// isInterrupted reports whether a developer's day is taken by on-call duty
+// leaving them no capacity, or a full day of leave. On-call duty with capacity
+// to spare only slows their tasks down.
+func (s *Scheduler) isInterrupted(dev *Developer, date time.Time) bool {
+	if oncall := s.findOnCall(dev, date); oncall != nil && s.coverage(dev, date, oncall.StartTime, oncall.EndTime) >= 1 && s.onCallRowCapacity(dev, *oncall) <= 0 {
+		return true
+	}
+	return s.leaveCapacity(dev, date) <= 0
+}
+
+// absentWorkdays counts the team working days in a row from date on which a
+// developer cannot work, up to releaseAbsenceWorkdays.
+func (s *Scheduler) absentWorkdays(dev *Developer, date time.Time) int {
+	absent := 0
+	for day := date; absent < releaseAbsenceWorkdays; day = s.addWorkdays(day, 1) {
+		if s.isDevWorking(dev, day) {
+			break
+		}
+		if s.isTeamWorkingDay(day) {
+			absent++
+		}
+	}
+	return absent
+}
+
+// takeOffTask removes dev from a task from a moment on, ending their
+// assignment as interrupted, and re-projects the task's end date from the
+// developers left on it. Taken off part way through a day, they still work on
+// the task until then. by names the preempting task, empty when the developer
+// is released for an absence. A task left without developers waits to be
+// picked up again.
+func (s *Scheduler) takeOffTask(task *Task, dev *Developer, from time.Time, by string) {
+	date := dayOf(from)
+
+	// Work already recorded past from no longer happens
+	ledger := task.Ledger[:0]
+	for _, entry := range task.Ledger {
+		if entry.Developer != dev.Name || !sameDay(entry.Date, date) || !entry.End.After(from) {
+			ledger = append(ledger, entry)
+		}
+	}
+	task.Ledger = ledger
+	s.rebook(dev, date)
+	if from.After(date) {
+		s.recordWork(task, dev, date, s.startNotBefore(task, date), from)
+	}
+
+	if a := task.openAssignment(dev.Name); a != nil {
+		a.Interrupted = true
+		a.PreemptedBy = by
+		task.endAssignment(a, s.subtractWorkdays(date, 1))
+	}
+
+	remaining := make([]*Developer, 0, len(task.AssignedDevs))
+	for _, d := range task.AssignedDevs {
+		if d != dev {
+			remaining = append(remaining, d)
+		}
+	}
+	task.AssignedDevs = remaining
+
+	if len(remaining) > 0 {
+		task.EndTime = s.calculateEndDate(task, remaining, date, task.remainingEffort())
+		for _, d := range remaining {
+			s.refreshNextFreeTime(d)
+		}
+	}
+	s.refreshNextFreeTime(dev)
+}
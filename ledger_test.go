@@ -0,0 +1,166 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRemainingEffort(t *testing.T) {
+	task := &Task{
+		Effort:          5,
+		CompletedEffort: 1,
+		Ledger: []EffortEntry{
+			{Developer: "Dev1", Effort: 1.5},
+			{Developer: "Dev2", Effort: 0.5},
+		},
+	}
+	if got := task.remainingEffort(); got != 2 {
+		t.Errorf("remaining effort = %v, want 2", got)
+	}
+	if got := task.deliveredEffort("Dev1"); got != 1.5 {
+		t.Errorf("effort delivered by Dev1 = %v, want 1.5", got)
+	}
+}
+
+func TestRecordProgressAcrossAbsences(t *testing.T) {
+	day := func(d, h, m int) time.Time { return time.Date(2026, 10, d, h, m, 0, 0, time.UTC) }
+	tests := []struct {
+		name        string
+		effort      float64
+		leaves      []Leave
+		ledger      []string // Day and hours worked, "01-02 15:04-15:04"
+		assignments int
+	}{
+		{
+			name:        "no leave",
+			effort:      2,
+			ledger:      []string{"10-12 09:00-17:00", "10-13 09:00-17:00"},
+			assignments: 1,
+		},
+		{
+			name:        "short leave slows the task down",
+			effort:      3,
+			leaves:      []Leave{{DevName: "Dev1", StartTime: day(13, 0, 0), EndTime: day(14, 0, 0), Fraction: 1}},
+			ledger:      []string{"10-12 09:00-17:00", "10-15 09:00-17:00", "10-16 09:00-17:00"},
+			assignments: 1,
+		},
+		{
+			name:        "part-day leave",
+			effort:      2,
+			leaves:      []Leave{{DevName: "Dev1", StartTime: day(13, 0, 0), EndTime: day(13, 0, 0), Fraction: 0.5}},
+			ledger:      []string{"10-12 09:00-17:00", "10-13 09:00-13:00", "10-14 09:00-13:00"},
+			assignments: 1,
+		},
+		{
+			name:        "leave by the hour",
+			effort:      2,
+			leaves:      []Leave{{DevName: "Dev1", StartTime: day(13, 9, 0), EndTime: day(13, 13, 0), Fraction: 1}},
+			ledger:      []string{"10-12 09:00-17:00", "10-13 13:00-17:00", "10-14 09:00-13:00"},
+			assignments: 1,
+		},
+		{
+			name:        "long leave releases the developer",
+			effort:      3,
+			leaves:      []Leave{{DevName: "Dev1", StartTime: day(13, 0, 0), EndTime: day(20, 0, 0), Fraction: 1}},
+			ledger:      []string{"10-12 09:00-17:00", "10-21 09:00-17:00", "10-22 09:00-17:00"},
+			assignments: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newWIPScheduler(1, 0, tt.effort)
+			s.leaves = tt.leaves
+			s.simulate(monday)
+			task := s.tasks[0]
+
+			var ledger []string
+			for _, entry := range task.Ledger {
+				ledger = append(ledger, entry.Date.Format("01-02 ")+entry.Start.Format("15:04-")+entry.End.Format("15:04"))
+			}
+			if !reflect.DeepEqual(ledger, tt.ledger) {
+				t.Errorf("ledger = %v, want %v", ledger, tt.ledger)
+			}
+			if got := task.remainingEffort(); got > effortEpsilon {
+				t.Errorf("remaining effort = %v, want 0", got)
+			}
+			if len(task.Assignments) != tt.assignments {
+				t.Errorf("%d assignments, want %d", len(task.Assignments), tt.assignments)
+			}
+		})
+	}
+}
+
+func TestSplitModeReleases(t *testing.T) {
+	tuesday := time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC)
+	none, half := 0.0, 0.5
+	tests := []struct {
+		name        string
+		split       bool
+		oncalls     []OnCall
+		leaves      []Leave
+		ledger      []string
+		assignments int
+	}{
+		{
+			name:        "on call without split mode stretches the task",
+			oncalls:     []OnCall{{DevName: "Dev1", StartTime: tuesday, EndTime: tuesday, Capacity: &none}},
+			ledger:      []string{"10-12 09:00-17:00", "10-14 09:00-17:00", "10-15 09:00-17:00"},
+			assignments: 1,
+		},
+		{
+			name:        "on call with no capacity releases the developer",
+			split:       true,
+			oncalls:     []OnCall{{DevName: "Dev1", StartTime: tuesday, EndTime: tuesday, Capacity: &none}},
+			ledger:      []string{"10-12 09:00-17:00", "10-14 09:00-17:00", "10-15 09:00-17:00"},
+			assignments: 2,
+		},
+		{
+			name:        "on call with capacity left keeps the developer on",
+			split:       true,
+			oncalls:     []OnCall{{DevName: "Dev1", StartTime: tuesday, EndTime: tuesday, Capacity: &half}},
+			ledger:      []string{"10-12 09:00-17:00", "10-13 09:00-13:00", "10-14 09:00-17:00", "10-15 09:00-13:00"},
+			assignments: 1,
+		},
+		{
+			name:        "full day of leave releases the developer",
+			split:       true,
+			leaves:      []Leave{{DevName: "Dev1", StartTime: tuesday, EndTime: tuesday, Fraction: 1}},
+			ledger:      []string{"10-12 09:00-17:00", "10-14 09:00-17:00", "10-15 09:00-17:00"},
+			assignments: 2,
+		},
+		{
+			name:        "part-day leave keeps the developer on",
+			split:       true,
+			leaves:      []Leave{{DevName: "Dev1", StartTime: tuesday, EndTime: tuesday, Fraction: 0.5}},
+			ledger:      []string{"10-12 09:00-17:00", "10-13 09:00-13:00", "10-14 09:00-17:00", "10-15 09:00-13:00"},
+			assignments: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newWIPScheduler(1, 0, 3)
+			s.splitTasks = tt.split
+			s.oncalls = tt.oncalls
+			s.leaves = tt.leaves
+			s.simulate(monday)
+			task := s.tasks[0]
+
+			var ledger []string
+			for _, entry := range task.Ledger {
+				ledger = append(ledger, entry.Date.Format("01-02 ")+entry.Start.Format("15:04-")+entry.End.Format("15:04"))
+			}
+			if !reflect.DeepEqual(ledger, tt.ledger) {
+				t.Errorf("ledger = %v, want %v", ledger, tt.ledger)
+			}
+			if len(task.Assignments) != tt.assignments {
+				t.Fatalf("%d assignments, want %d", len(task.Assignments), tt.assignments)
+			}
+			if first := task.Assignments[0]; tt.assignments > 1 && (!first.Interrupted || first.PreemptedBy != "" || !sameDay(first.End, monday)) {
+				t.Errorf("first assignment = %+v, want one interrupted after Monday", *first)
+			}
+		})
+	}
+}
@@ -2,18 +2,28 @@ package main
 
 import (
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"math"
 	"mime/multipart"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// lastSchedule holds the scheduler from the most recent successful upload so
+// follow-up endpoints can report on it without re-uploading the CSVs.
+var (
+	lastScheduleMu sync.RWMutex
+	lastSchedule   *Scheduler
+)
+
 func main() {
 	r := gin.Default()
 
@@ -30,100 +40,106 @@ func main() {
 
 	// Handle CSV uploads
 	r.POST("/upload", func(c *gin.Context) {
-		gin.SetMode(gin.ReleaseMode)
-		// Get files from form
-		rolesFile, err := c.FormFile("roles.csv")
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing roles file"})
+		scheduler, ok := schedulerFromUpload(c)
+		if !ok {
 			return
 		}
-
-		tasksFile, err := c.FormFile("tasks.csv")
+		startDate, err := startDateFromForm(c)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing tasks file"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		scheduler.Schedule(startDate)
 
-		devsFile, err := c.FormFile("developers.csv")
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing developers file"})
+		lastScheduleMu.Lock()
+		lastSchedule = scheduler
+		lastScheduleMu.Unlock()
+
+		// Return timeline data
+		timelineData := processScheduleToTimelineData(scheduler)
+		c.JSON(http.StatusOK, timelineData)
+	})
+
+	// Monte Carlo forecast over sampled task efforts
+	r.POST("/forecast", func(c *gin.Context) {
+		scheduler, ok := schedulerFromUpload(c)
+		if !ok {
 			return
 		}
 
-		oncallsFile, err := c.FormFile("oncalls.csv")
+		runs, err := strconv.Atoi(c.DefaultPostForm("runs", strconv.Itoa(defaultForecastRuns)))
+		if err != nil || runs <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "runs must be a positive integer"})
+			return
+		}
+		seed, err := strconv.ParseInt(c.DefaultPostForm("seed", "1"), 10, 64)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing oncalls file"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "seed must be an integer"})
 			return
 		}
 
-		leavesFile, err := c.FormFile("leaves.csv")
+		startDate, err := startDateFromForm(c)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing leaves file"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		// Save uploaded files temporarily
-		tempFiles := make([]string, 5)
-		uploadedFiles := []*multipart.FileHeader{rolesFile, tasksFile, devsFile, oncallsFile, leavesFile}
+		c.JSON(http.StatusOK, scheduler.Forecast(startDate, runs, seed))
+	})
 
-		for i, file := range uploadedFiles {
-			tempFile := "temp_" + file.Filename
-			if err := c.SaveUploadedFile(file, tempFile); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save uploaded file"})
-				return
-			}
-			tempFiles[i] = tempFile
-			defer os.Remove(tempFile)
+	// Side-by-side comparison of what-if scenarios against the upload
+	r.POST("/scenarios", func(c *gin.Context) {
+		scheduler, ok := schedulerFromUpload(c)
+		if !ok {
+			return
 		}
 
-		// Load data from CSVs
-		tasks, developers, roles, err := LoadFromCSV(tempFiles[0], tempFiles[1], tempFiles[2])
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		var scenarios []Scenario
+		if err := json.Unmarshal([]byte(c.PostForm("scenarios")), &scenarios); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid scenarios: %v", err)})
 			return
 		}
 
-		// Load oncalls and leaves
-		oncalls, err := loadOncalls(tempFiles[3])
+		startDate, err := startDateFromForm(c)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		leaves, err := loadLeaves(tempFiles[4])
+		comparison, err := scheduler.CompareScenarios(startDate, scenarios)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
+		c.JSON(http.StatusOK, comparison)
+	})
 
-		hasCyclicDependencies := func(tasks []*Task) bool {
-			visited := make(map[string]bool)
-			recStack := make(map[string]bool)
+	// Critical path of the most recently uploaded schedule
+	r.GET("/schedule/critical-path", func(c *gin.Context) {
+		lastScheduleMu.RLock()
+		scheduler := lastSchedule
+		lastScheduleMu.RUnlock()
 
-			for _, task := range tasks {
-				if !visited[task.Name] {
-					if detectCycle(task, visited, recStack, tasks) {
-						return true
-					}
-				}
-			}
-			return false
-		}
-
-		// Check for cyclic dependencies
-		if hasCyclicDependencies(tasks) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Cyclic dependencies found"})
+		if scheduler == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No schedule available, upload files first"})
 			return
 		}
+		c.JSON(http.StatusOK, scheduler.criticalPath)
+	})
 
-		// Create scheduler and process tasks
-		scheduler := NewScheduler(tasks, developers, roles, oncalls, leaves)
-		scheduler.Schedule(time.Now())
+	// Due date violations of the most recently uploaded schedule
+	r.GET("/schedule/deadlines", func(c *gin.Context) {
+		lastScheduleMu.RLock()
+		scheduler := lastSchedule
+		lastScheduleMu.RUnlock()
 
-		// Return timeline data
-		timelineData := processScheduleToTimelineData(scheduler)
-		c.JSON(http.StatusOK, timelineData)
+		if scheduler == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No schedule available, upload files first"})
+			return
+		}
+		c.JSON(http.StatusOK, scheduler.deadlineViolations)
 	})
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -131,50 +147,351 @@ func main() {
 	r.Run(":" + port)
 }
 
+// schedulerFromUpload loads the uploaded CSVs and form options into a new
+// scheduler. On failure it writes the error response and returns false.
+func schedulerFromUpload(c *gin.Context) (*Scheduler, bool) {
+	gin.SetMode(gin.ReleaseMode)
+	// Get files from form
+	rolesFile, err := c.FormFile("roles.csv")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing roles file"})
+		return nil, false
+	}
+
+	tasksFile, err := c.FormFile("tasks.csv")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing tasks file"})
+		return nil, false
+	}
+
+	devsFile, err := c.FormFile("developers.csv")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing developers file"})
+		return nil, false
+	}
+
+	oncallsFile, err := c.FormFile("oncalls.csv")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing oncalls file"})
+		return nil, false
+	}
+
+	leavesFile, err := c.FormFile("leaves.csv")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing leaves file"})
+		return nil, false
+	}
+
+	// Save uploaded files temporarily
+	tempFiles := make([]string, 5)
+	uploadedFiles := []*multipart.FileHeader{rolesFile, tasksFile, devsFile, oncallsFile, leavesFile}
+
+	for i, file := range uploadedFiles {
+		tempFile := "temp_" + file.Filename
+		if err := c.SaveUploadedFile(file, tempFile); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save uploaded file"})
+			return nil, false
+		}
+		tempFiles[i] = tempFile
+		defer os.Remove(tempFile)
+	}
+
+	// Load data from CSVs
+	// Subtask rules are optional
+	var rules []SubtaskRule
+	if rulesFile, err := c.FormFile("subtasks.yaml"); err == nil {
+		tempFile := "temp_" + rulesFile.Filename
+		if err := c.SaveUploadedFile(rulesFile, tempFile); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save uploaded file"})
+			return nil, false
+		}
+		defer os.Remove(tempFile)
+
+		rules, err = loadSubtaskRules(tempFile)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+	}
+
+	// The overhead model is optional
+	overhead := defaultOverhead
+	if overheadFile, err := c.FormFile("overhead.yaml"); err == nil {
+		tempFile := "temp_" + overheadFile.Filename
+		if err := c.SaveUploadedFile(overheadFile, tempFile); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save uploaded file"})
+			return nil, false
+		}
+		defer os.Remove(tempFile)
+
+		overhead, err = loadOverheadConfig(tempFile)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+	}
+
+	tasks, developers, roles, err := LoadFromCSV(tempFiles[0], tempFiles[1], tempFiles[2], rules, overhead)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	// Load oncalls and leaves
+	oncalls, err := loadOncalls(tempFiles[3])
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	leaves, err := loadLeaves(tempFiles[4])
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if err := validateLeaves(leaves, developers); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if err := validateTaskConstraints(tasks, developers); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	// Progress is optional
+	var progress []TaskProgress
+	if progressFile, err := c.FormFile("progress.csv"); err == nil {
+		tempFile := "temp_" + progressFile.Filename
+		if err := c.SaveUploadedFile(progressFile, tempFile); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save uploaded file"})
+			return nil, false
+		}
+		defer os.Remove(tempFile)
+
+		progress, err = loadProgress(tempFile)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		if err := validateProgress(progress, tasks, developers); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+	}
+
+	// Holidays are optional
+	var holidays []Holiday
+	if holidaysFile, err := c.FormFile("holidays.csv"); err == nil {
+		tempFile := "temp_" + holidaysFile.Filename
+		if err := c.SaveUploadedFile(holidaysFile, tempFile); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save uploaded file"})
+			return nil, false
+		}
+		defer os.Remove(tempFile)
+
+		holidays, err = loadHolidays(tempFile)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+	}
+
+	hasCyclicDependencies := func(tasks []*Task) bool {
+		visited := make(map[string]bool)
+		recStack := make(map[string]bool)
+
+		for _, task := range tasks {
+			if !visited[task.Name] {
+				if detectCycle(task, visited, recStack, tasks) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	// Check for cyclic dependencies
+	if hasCyclicDependencies(tasks) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cyclic dependencies found"})
+		return nil, false
+	}
+
+	// Create scheduler and process tasks
+	scheduler := NewScheduler(tasks, developers, roles, oncalls, leaves, holidays)
+	scheduler.progress = progress
+	scheduler.overhead = overhead
+	if rules != nil {
+		scheduler.subtaskRules = rules
+	}
+	scheduler.deadlineAware = c.PostForm("deadlineAware") == "true"
+	scheduler.splitTasks = c.PostForm("splitTasks") == "true"
+	strategy, err := newScheduleStrategy(c.PostForm("strategy"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	scheduler.strategy = strategy
+	policy, err := newAssignmentPolicy(c.PostForm("policy"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	// Deadline-aware ordering replaces the policy's task order
+	if name := c.PostForm("policy"); scheduler.deadlineAware && name != "" && name != "priority" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("deadlineAware cannot be combined with the %s policy", name)})
+		return nil, false
+	}
+	scheduler.policy = policy
+	if value := c.PostForm("contextSwitchPenalty"); value != "" {
+		penalty, err := strconv.ParseFloat(value, 64)
+		if err != nil || penalty < 0 || penalty >= 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "contextSwitchPenalty must be at least 0 and below 1"})
+			return nil, false
+		}
+		scheduler.contextSwitchPenalty = penalty
+	}
+	if value := c.PostForm("preemptPriorities"); value != "" {
+		preemptive, err := parsePreemptivePriorities(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		scheduler.preemptive = preemptive
+	}
+	if pattern := c.PostForm("workWeek"); pattern != "" {
+		workWeek, err := parseWorkWeek(pattern)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		scheduler.workWeek = workWeek
+	}
+	return scheduler, true
+}
+
+// Timeline item kinds.
+const (
+	KindTask   = "task"
+	KindOnCall = "oncall"
+	KindLeave  = "leave"
+)
+
 type TimelineItem struct {
-	ID      string `json:"id"`
-	Start   string `json:"start"`
-	End     string `json:"end"`
-	Content string `json:"content"`
+	ID           string   `json:"id"`
+	Start        string   `json:"start"`
+	End          string   `json:"end"`
+	Content      string   `json:"content"`
+	Kind         string   `json:"kind"`
+	Task         string   `json:"task,omitempty"`
+	Developer    string   `json:"developer"`
+	TaskType     string   `json:"taskType,omitempty"`
+	Priority     int      `json:"priority,omitempty"`
+	Effort       float64  `json:"effort,omitempty"`
+	RawEffort    float64  `json:"rawEffort,omitempty"`
+	Dependencies []string `json:"dependencies,omitempty"`
+	Links        []string `json:"links,omitempty"`
+	Parent       string   `json:"parent,omitempty"`
+	Critical     bool     `json:"critical,omitempty"`
+	DueDate      string   `json:"dueDate,omitempty"`
+	WorkdaysLate int      `json:"workdaysLate,omitempty"`
+	PreemptedBy  string   `json:"preemptedBy,omitempty"`
+	Allocation   float64  `json:"allocation,omitempty"` // Share of the developer's capacity
+	Delivered    float64  `json:"delivered,omitempty"`  // Effort the developer delivered
 }
 
 func processScheduleToTimelineData(s *Scheduler) []TimelineItem {
 	var items []TimelineItem
 
-	// Process tasks into timeline items
+	// Describe each scheduled task once
+	bases := make(map[string]TimelineItem)
 	for _, task := range s.tasks {
 		if task.StartTime.IsZero() || task.EndTime.IsZero() {
 			continue // Skip unscheduled tasks
 		}
 
-		// Create timeline items for each developer assigned to the task
-		for devName, startTime := range task.DevStartTimes {
-			items = append(items, TimelineItem{
-				ID:      fmt.Sprintf("task_%s_%s", task.Name, devName),
-				Start:   startTime.Format("2006-01-02"),
-				End:     task.EndTime.Format("2006-01-02"),
-				Content: fmt.Sprintf("Task: %s (Assigned to: %s)", task.Name, devName),
-			})
+		var dueDate string
+		if !task.DueDate.IsZero() {
+			dueDate = task.DueDate.Format("2006-01-02")
 		}
+
+		var links []string
+		for _, depName := range task.Dependencies {
+			links = append(links, fmt.Sprintf("%s:%s", depName, task.linkTo(depName)))
+		}
+
+		bases[task.Name] = TimelineItem{
+			Kind:         KindTask,
+			Task:         task.Name,
+			TaskType:     task.TaskType,
+			Priority:     task.Priority,
+			Effort:       task.Effort,
+			RawEffort:    task.RawEffort,
+			Dependencies: task.Dependencies,
+			Links:        links,
+			Parent:       task.Parent,
+			Critical:     task.IsCritical,
+			DueDate:      dueDate,
+			WorkdaysLate: s.workdaysLate(task),
+		}
+	}
+
+	// Create a timeline item for each assignment
+	segments := make(map[string]int)
+	for _, a := range s.Assignments() {
+		base, ok := bases[a.Task]
+		if !ok {
+			continue
+		}
+		item := base
+		key := fmt.Sprintf("task_%s_%s", a.Task, a.Developer)
+		item.ID = key
+		if n := segments[key]; n > 0 {
+			item.ID = fmt.Sprintf("%s_%d", key, n)
+		}
+		segments[key]++
+		item.Start = a.Start.Format(time.RFC3339)
+		item.End = a.End.Format(time.RFC3339)
+		item.Content = fmt.Sprintf("Task: %s (Assigned to: %s)", a.Task, a.Developer)
+		switch {
+		case a.PreemptedBy != "":
+			item.Content = fmt.Sprintf("Task: %s (Assigned to: %s, preempted by %s)", a.Task, a.Developer, a.PreemptedBy)
+		case a.Interrupted:
+			item.Content = fmt.Sprintf("Task: %s (Assigned to: %s, interrupted)", a.Task, a.Developer)
+		}
+		item.Developer = a.Developer
+		item.Allocation = a.Fraction
+		item.Delivered = math.Round(a.Effort*100) / 100
+		item.PreemptedBy = a.PreemptedBy
+		items = append(items, item)
 	}
 
 	// Add oncall periods
 	for i, oncall := range s.oncalls {
 		items = append(items, TimelineItem{
-			ID:      fmt.Sprintf("oncall_%d", i),
-			Start:   oncall.StartTime.Format("2006-01-02"),
-			End:     oncall.EndTime.Format("2006-01-02"),
-			Content: fmt.Sprintf("On-call: %s", oncall.DevName),
+			ID:        fmt.Sprintf("oncall_%d", i),
+			Start:     oncall.StartTime.Format(time.RFC3339),
+			End:       periodEnd(oncall.EndTime).Format(time.RFC3339),
+			Content:   fmt.Sprintf("On-call: %s", oncall.DevName),
+			Kind:      KindOnCall,
+			Developer: oncall.DevName,
 		})
 	}
 
 	// Add leave periods
 	for i, leave := range s.leaves {
+		content := fmt.Sprintf("Leave: %s", leave.DevName)
+		if leave.Fraction < 1 {
+			content = fmt.Sprintf("Leave: %s (%.0f%%)", leave.DevName, leave.Fraction*100)
+		}
 		items = append(items, TimelineItem{
-			ID:      fmt.Sprintf("leave_%d", i),
-			Start:   leave.StartTime.Format("2006-01-02"),
-			End:     leave.EndTime.Format("2006-01-02"),
-			Content: fmt.Sprintf("Leave: %s", leave.DevName),
+			ID:        fmt.Sprintf("leave_%d", i),
+			Start:     leave.StartTime.Format(time.RFC3339),
+			End:       periodEnd(leave.EndTime).Format(time.RFC3339),
+			Content:   content,
+			Kind:      KindLeave,
+			Developer: leave.DevName,
 		})
 	}
 
@@ -218,14 +535,28 @@ func loadOncalls(filename string) ([]OnCall, error) {
 		return nil, err
 	}
 
-	for _, record := range records[1:] { // Skip header
-		startTime, _ := time.Parse("2006-01-02", record[1])
-		endTime, _ := time.Parse("2006-01-02", record[2])
-		oncalls = append(oncalls, OnCall{
+	for i, record := range records[1:] { // Skip header
+		startTime, err := parseTimestamp(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid on-call start on row %d for %s: %v", i+2, record[0], err)
+		}
+		endTime, err := parseTimestamp(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid on-call end on row %d for %s: %v", i+2, record[0], err)
+		}
+		oncall := OnCall{
 			DevName:   record[0],
 			StartTime: startTime,
 			EndTime:   endTime,
-		})
+		}
+		if len(record) > 3 && record[3] != "" {
+			capacity, err := strconv.ParseFloat(record[3], 64)
+			if err != nil || capacity < 0 || capacity > 1 {
+				return nil, fmt.Errorf("invalid on-call capacity %q for %s: must be between 0 and 1", record[3], record[0])
+			}
+			oncall.Capacity = &capacity
+		}
+		oncalls = append(oncalls, oncall)
 	}
 	return oncalls, nil
 }
@@ -237,27 +568,180 @@ func loadLeaves(filename string) ([]Leave, error) {
 		return nil, err
 	}
 
-	for _, record := range records[1:] { // Skip header
-		startTime, _ := time.Parse("2006-01-02", record[1])
-		endTime, _ := time.Parse("2006-01-02", record[2])
-		leaves = append(leaves, Leave{
+	for i, record := range records[1:] { // Skip header
+		startTime, err := parseTimestamp(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid leave start on row %d for %s: %v", i+2, record[0], err)
+		}
+		endTime, err := parseTimestamp(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid leave end on row %d for %s: %v", i+2, record[0], err)
+		}
+		leave := Leave{
 			DevName:   record[0],
 			StartTime: startTime,
 			EndTime:   endTime,
-		})
+			Fraction:  1,
+		}
+		if len(record) > 3 && record[3] != "" {
+			fraction, err := strconv.ParseFloat(record[3], 64)
+			if err != nil || fraction <= 0 || fraction > 1 {
+				return nil, fmt.Errorf("invalid leave fraction %q for %s: must be greater than 0 and at most 1", record[3], record[0])
+			}
+			leave.Fraction = fraction
+		}
+		leaves = append(leaves, leave)
 	}
 	return leaves, nil
 }
 
-func LoadFromCSV(rolesFile, tasksFile, devsFile string) ([]*Task, []*Developer, map[string]*Role, error) {
+// validateTaskConstraints rejects allowed or forbidden developers that are
+// missing from developers.csv.
+func validateTaskConstraints(tasks []*Task, developers []*Developer) error {
+	known := make(map[string]bool)
+	for _, dev := range developers {
+		known[dev.Name] = true
+	}
+
+	for _, task := range tasks {
+		for _, devName := range append(append([]string{}, task.AllowedDevs...), task.ForbiddenDevs...) {
+			if !known[devName] {
+				return fmt.Errorf("task %s references unknown developer %q", task.Name, devName)
+			}
+		}
+	}
+	return nil
+}
+
+func splitNames(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// startDateFromForm reads the optional startDate form field, defaulting to
+// now when it is absent.
+func startDateFromForm(c *gin.Context) (time.Time, error) {
+	value := c.PostForm("startDate")
+	if value == "" {
+		// Dates are compared as whole days, so drop the time of day
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	startDate, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid start date %q: %v", value, err)
+	}
+	return startDate, nil
+}
+
+func loadProgress(filename string) ([]TaskProgress, error) {
+	var progress []TaskProgress
+	records, err := readCSV(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records[1:] { // Skip header
+		p := TaskProgress{Task: record[0]}
+		if record[1] != "" {
+			if p.ActualStart, err = parseTimestamp(record[1]); err != nil {
+				return nil, fmt.Errorf("invalid actual start %q for task %s: %v", record[1], record[0], err)
+			}
+		}
+		if record[2] != "" {
+			p.PercentComplete, err = strconv.ParseFloat(record[2], 64)
+			if err != nil || p.PercentComplete < 0 || p.PercentComplete > 100 {
+				return nil, fmt.Errorf("invalid percent complete %q for task %s: must be between 0 and 100", record[2], record[0])
+			}
+		}
+		if len(record) > 3 && record[3] != "" {
+			remaining, err := parseEffortValue(record[3])
+			if err != nil || remaining < 0 {
+				return nil, fmt.Errorf("invalid remaining effort %q for task %s", record[3], record[0])
+			}
+			p.RemainingEffort = &remaining
+		}
+		if len(record) > 4 && record[4] != "" {
+			p.Developers = splitNames(record[4])
+		}
+		if len(record) > 5 && record[5] != "" {
+			if p.ActualEnd, err = parseTimestamp(record[5]); err != nil {
+				return nil, fmt.Errorf("invalid actual end %q for task %s: %v", record[5], record[0], err)
+			}
+		}
+		progress = append(progress, p)
+	}
+	return progress, nil
+}
+
+// validateLeaves rejects leave rows for developers missing from developers.csv.
+func validateLeaves(leaves []Leave, developers []*Developer) error {
+	known := make(map[string]bool)
+	for _, dev := range developers {
+		known[dev.Name] = true
+	}
+
+	var unknown []string
+	for _, leave := range leaves {
+		if !known[leave.DevName] {
+			unknown = append(unknown, leave.DevName)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("leaves reference unknown developers: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+func loadHolidays(filename string) ([]Holiday, error) {
+	var holidays []Holiday
+	records, err := readCSV(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records[1:] { // Skip header
+		date, err := time.Parse("2006-01-02", record[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid holiday date %q: %v", record[0], err)
+		}
+		holiday := Holiday{Date: date}
+		if len(record) > 1 {
+			holiday.Name = record[1]
+		}
+		if len(record) > 2 {
+			holiday.Region = strings.TrimSpace(record[2])
+		}
+		holidays = append(holidays, holiday)
+	}
+	return holidays, nil
+}
+
+// LoadFromCSV loads roles, tasks and developers, deriving subtasks from each
+// task row with rules, or the built-in frontend and QA rules when nil, and
+// inflating every task's effort with the overhead model.
+func LoadFromCSV(rolesFile, tasksFile, devsFile string, rules []SubtaskRule, overhead OverheadConfig) ([]*Task, []*Developer, map[string]*Role, error) {
 	// Load Roles
 	roles := make(map[string]*Role)
 	if roleRecords, err := readCSV(rolesFile); err == nil {
 		for _, record := range roleRecords[1:] { // Skip header
 			availability, _ := strconv.ParseFloat(record[1], 64)
+			var onCallCapacity float64
+			if len(record) > 2 && record[2] != "" {
+				onCallCapacity, err = strconv.ParseFloat(record[2], 64)
+				if err != nil || onCallCapacity < 0 || onCallCapacity > 1 {
+					return nil, nil, nil, fmt.Errorf("invalid on-call capacity %q for role %s: must be between 0 and 1", record[2], record[0])
+				}
+			}
 			roles[record[0]] = &Role{
 				Name:                record[0],
 				AvailabilityPercent: availability,
+				OnCallCapacity:      onCallCapacity,
 			}
 		}
 	} else {
@@ -266,24 +750,68 @@ func LoadFromCSV(rolesFile, tasksFile, devsFile string) ([]*Task, []*Developer,
 
 	// Load Tasks
 	var tasks []*Task
+	if rules == nil {
+		rules = defaultSubtaskRules
+	}
 	if taskRecords, err := readCSV(tasksFile); err == nil {
+		columns, err := subtaskColumns(taskRecords[0], rules)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 		for _, record := range taskRecords[1:] { // Skip header
 			priority, _ := strconv.Atoi(record[2])
-			effort, _ := strconv.ParseFloat(record[3], 64)
+			effort, estimate, err := parseEffort(record[3])
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("invalid effort for task %s: %v", record[0], err)
+			}
 			parallel, _ := strconv.Atoi(record[4])
-			dependencies := strings.Split(record[5], ",")
-			if record[5] == "" {
-				dependencies = []string{}
+			dependencies := []string{}
+			var links []DependencyLink
+			if record[5] != "" {
+				for _, entry := range strings.Split(record[5], ",") {
+					link := parseDependency(entry)
+					dependencies = append(dependencies, link.Task)
+					links = append(links, link)
+				}
+			}
+
+			// Parse optional due date
+			var dueDate time.Time
+			if len(record) > 8 && record[8] != "" {
+				dueDate, err = time.Parse("2006-01-02", record[8])
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf("invalid due date %q for task %s: %v", record[8], record[0], err)
+				}
 			}
 
-			// Parse FE and QA required flags
-			needsFE := false
-			needsQA := false
-			if len(record) > 6 {
-				needsFE = record[6] == "true"
+			// Parse optional minimum proficiency
+			var minProficiency float64
+			if len(record) > 9 && record[9] != "" {
+				minProficiency, err = strconv.ParseFloat(record[9], 64)
+				if err != nil || minProficiency < 0 || minProficiency > 1 {
+					return nil, nil, nil, fmt.Errorf("invalid minimum proficiency %q for task %s: must be between 0 and 1", record[9], record[0])
+				}
+			}
+
+			// Parse optional assignment constraints
+			var allowedDevs, forbiddenDevs []string
+			if len(record) > 10 && record[10] != "" {
+				allowedDevs = splitNames(record[10])
+			}
+			if len(record) > 11 && record[11] != "" {
+				forbiddenDevs = splitNames(record[11])
 			}
-			if len(record) > 7 {
-				needsQA = record[7] == "true"
+			var earliestStart time.Time
+			if len(record) > 12 && record[12] != "" {
+				earliestStart, err = parseTimestamp(record[12])
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf("invalid earliest start %q for task %s: %v", record[12], record[0], err)
+				}
+			}
+
+			var team string
+			if i, ok := columns["Team"]; ok && i < len(record) {
+				team = strings.TrimSpace(record[i])
 			}
 
 			// Create main task
@@ -291,52 +819,29 @@ func LoadFromCSV(rolesFile, tasksFile, devsFile string) ([]*Task, []*Developer,
 			mainTask := &Task{
 				Name:           taskName,
 				TaskType:       record[1],
+				Team:           team,
+				RawEffort:      effort,
+				MinProficiency: minProficiency,
+				AllowedDevs:    allowedDevs,
+				ForbiddenDevs:  forbiddenDevs,
+				EarliestStart:  earliestStart,
 				Priority:       priority,
 				ParallelFactor: parallel,
 				Dependencies:   dependencies,
+				Links:          links,
+				DueDate:        dueDate,
 				IsCompleted:    false,
 			}
 
-			// Increase effort by (10*parallelFactor + 40)%
-			effortIncrease := 1.0 + float64(10*parallel+40)/100.0
-			mainTask.Effort = math.Round(effort * effortIncrease)
+			overhead.inflate(mainTask, estimate)
 
 			tasks = append(tasks, mainTask)
 
-			// Add Frontend task if flag is true
-			var feTaskName string
-			if needsFE {
-				feTaskName = taskName + "_Frontend"
-				feTask := &Task{
-					Name:           feTaskName,
-					TaskType:       "Frontend",
-					Priority:       priority,
-					Effort:         math.Round(effort * 0.25 * effortIncrease),
-					ParallelFactor: 1,
-					Dependencies:   []string{taskName},
-					IsCompleted:    false,
-				}
-				tasks = append(tasks, feTask)
-			}
-
-			// Add QA task if both flags are true
-			if needsQA {
-				qaTaskName := taskName + "_QA"
-				dependencies := []string{taskName}
-				if needsFE {
-					dependencies = append(dependencies, feTaskName)
-				}
-				qaTask := &Task{
-					Name:           qaTaskName,
-					TaskType:       "QA",
-					Priority:       priority,
-					Effort:         math.Round(effort * 0.25 * effortIncrease),
-					ParallelFactor: 1,
-					Dependencies:   dependencies,
-					IsCompleted:    false,
-				}
-				tasks = append(tasks, qaTask)
+			subtasks, err := deriveSubtasks(mainTask, estimate, rules, overhead, record, columns)
+			if err != nil {
+				return nil, nil, nil, err
 			}
+			tasks = append(tasks, subtasks...)
 		}
 	} else {
 		return nil, nil, nil, fmt.Errorf("error reading tasks: %v", err)
@@ -346,11 +851,73 @@ func LoadFromCSV(rolesFile, tasksFile, devsFile string) ([]*Task, []*Developer,
 	var developers []*Developer
 	if devRecords, err := readCSV(devsFile); err == nil {
 		for _, record := range devRecords[1:] { // Skip header
-			taskTypes := strings.Split(record[2], ",")
+			taskTypes, proficiency, err := parseTaskTypes(record[2])
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("invalid task types for developer %s: %v", record[0], err)
+			}
+			var location string
+			if len(record) > 3 {
+				location = strings.TrimSpace(record[3])
+			}
+			var workWeek WorkWeek
+			if len(record) > 4 && strings.TrimSpace(record[4]) != "" {
+				workWeek, err = parseWorkWeek(record[4])
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf("invalid work week for developer %s: %v", record[0], err)
+				}
+			}
+			var availability []AvailabilityPeriod
+			if len(record) > 5 && strings.TrimSpace(record[5]) != "" {
+				availability, err = parseAvailability(record[5])
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf("invalid availability for developer %s: %v", record[0], err)
+				}
+			}
+			var joinDate, exitDate time.Time
+			if len(record) > 6 && record[6] != "" {
+				if joinDate, err = time.Parse("2006-01-02", record[6]); err != nil {
+					return nil, nil, nil, fmt.Errorf("invalid join date %q for developer %s: %v", record[6], record[0], err)
+				}
+			}
+			if len(record) > 7 && record[7] != "" {
+				if exitDate, err = time.Parse("2006-01-02", record[7]); err != nil {
+					return nil, nil, nil, fmt.Errorf("invalid exit date %q for developer %s: %v", record[7], record[0], err)
+				}
+			}
+			var rampUp []RampUpStep
+			if len(record) > 8 && strings.TrimSpace(record[8]) != "" {
+				rampUp, err = parseRampUp(record[8])
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf("invalid ramp-up for developer %s: %v", record[0], err)
+				}
+			}
+			maxTasks := 1
+			if len(record) > 9 && strings.TrimSpace(record[9]) != "" {
+				maxTasks, err = strconv.Atoi(strings.TrimSpace(record[9]))
+				if err != nil || maxTasks < 1 {
+					return nil, nil, nil, fmt.Errorf("invalid max tasks %q for developer %s: must be a positive integer", record[9], record[0])
+				}
+			}
+			hoursPerDay := standardHoursPerDay
+			if len(record) > 10 && strings.TrimSpace(record[10]) != "" {
+				hoursPerDay, err = strconv.ParseFloat(strings.TrimSpace(record[10]), 64)
+				if err != nil || hoursPerDay <= 0 || hoursPerDay > 24 {
+					return nil, nil, nil, fmt.Errorf("invalid hours per day %q for developer %s: must be greater than 0 and at most 24", record[10], record[0])
+				}
+			}
 			developers = append(developers, &Developer{
-				Name:      record[0],
-				Role:      record[1],
-				TaskTypes: taskTypes,
+				Name:         record[0],
+				Role:         record[1],
+				TaskTypes:    taskTypes,
+				Proficiency:  proficiency,
+				Location:     location,
+				WorkWeek:     workWeek,
+				Availability: availability,
+				JoinDate:     joinDate,
+				ExitDate:     exitDate,
+				RampUp:       rampUp,
+				MaxTasks:     maxTasks,
+				HoursPerDay:  hoursPerDay,
 			})
 		}
 	} else {
@@ -360,6 +927,132 @@ func LoadFromCSV(rolesFile, tasksFile, devsFile string) ([]*Task, []*Developer,
 	return tasks, developers, roles, nil
 }
 
+// parseEffort parses either a single effort value or a PERT three-point
+// estimate written as "optimistic/likely/pessimistic". Values are in days, or
+// in hours with an "h" suffix. For an estimate the returned effort is its PERT
+// mean.
+func parseEffort(value string) (float64, Estimate, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) == 1 {
+		effort, err := parseEffortValue(value)
+		if err != nil || effort < 0 {
+			return 0, Estimate{}, fmt.Errorf("expected days or hours, e.g. 3 or 3h, got %q", value)
+		}
+		return effort, Estimate{}, nil
+	}
+	if len(parts) != 3 {
+		return 0, Estimate{}, fmt.Errorf("expected optimistic/likely/pessimistic, got %q", value)
+	}
+
+	var points [3]float64
+	for i, part := range parts {
+		p, err := parseEffortValue(part)
+		if err != nil || p < 0 {
+			return 0, Estimate{}, fmt.Errorf("invalid estimate %q", part)
+		}
+		points[i] = p
+	}
+	estimate := Estimate{Optimistic: points[0], Likely: points[1], Pessimistic: points[2]}
+	if estimate.Optimistic > estimate.Likely || estimate.Likely > estimate.Pessimistic {
+		return 0, Estimate{}, fmt.Errorf("estimate %q must satisfy optimistic <= likely <= pessimistic", value)
+	}
+	return estimate.Mean(), estimate, nil
+}
+
+// parseTaskTypes parses comma separated task types with an optional
+// proficiency level, e.g. "Backend:1.0,Frontend:0.6". Types without a level
+// are left out of the proficiency map.
+func parseTaskTypes(value string) ([]string, map[string]float64, error) {
+	var taskTypes []string
+	proficiency := make(map[string]float64)
+	for _, entry := range strings.Split(value, ",") {
+		name, level, hasLevel := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		taskTypes = append(taskTypes, name)
+		if !hasLevel {
+			continue
+		}
+		l, err := strconv.ParseFloat(strings.TrimSpace(level), 64)
+		if err != nil || l <= 0 || l > 1 {
+			return nil, nil, fmt.Errorf("invalid proficiency %q for %s: must be greater than 0 and at most 1", level, name)
+		}
+		proficiency[name] = l
+	}
+	return taskTypes, proficiency, nil
+}
+
+// parseAvailability parses semicolon separated availability periods of the
+// form "value" or "value@YYYY-MM-DD", e.g. "0.5;1.0@2025-04-01" for 0.5 until
+// the end of March and 1.0 afterwards.
+func parseAvailability(value string) ([]AvailabilityPeriod, error) {
+	var periods []AvailabilityPeriod
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		var period AvailabilityPeriod
+		percent, from, hasFrom := strings.Cut(entry, "@")
+		if hasFrom {
+			date, err := time.Parse("2006-01-02", strings.TrimSpace(from))
+			if err != nil {
+				return nil, fmt.Errorf("invalid date in %q: %v", entry, err)
+			}
+			period.From = date
+		}
+		p, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+		if err != nil || p < 0 || p > 1 {
+			return nil, fmt.Errorf("invalid availability %q: must be between 0 and 1", percent)
+		}
+		period.Percent = p
+		periods = append(periods, period)
+	}
+
+	sort.SliceStable(periods, func(i, j int) bool {
+		return periods[i].From.Before(periods[j].From)
+	})
+	return periods, nil
+}
+
+// parseRampUp parses semicolon separated ramp-up steps of the form
+// "fraction:duration", where duration is a number of days ("10d") or weeks
+// ("4w"), e.g. "0.5:4w;0.8:2w".
+func parseRampUp(value string) ([]RampUpStep, error) {
+	var steps []RampUpStep
+	for _, entry := range strings.Split(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fraction, duration, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid ramp-up step %q: expected fraction:duration", entry)
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(fraction), 64)
+		if err != nil || f < 0 || f > 1 {
+			return nil, fmt.Errorf("invalid ramp-up fraction %q: must be between 0 and 1", fraction)
+		}
+
+		duration = strings.TrimSpace(duration)
+		multiplier := 1
+		switch {
+		case strings.HasSuffix(duration, "w"):
+			multiplier = 7
+			duration = strings.TrimSuffix(duration, "w")
+		case strings.HasSuffix(duration, "d"):
+			duration = strings.TrimSuffix(duration, "d")
+		}
+		n, err := strconv.Atoi(duration)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid ramp-up duration in %q", entry)
+		}
+		steps = append(steps, RampUpStep{Fraction: f, Days: n * multiplier})
+	}
+	return steps, nil
+}
+
 func readCSV(filename string) ([][]string, error) {
 	file, err := os.Open(filename)
 	if err != nil {
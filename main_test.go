@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeCSV writes rows to a temporary CSV file and returns its path.
+func writeCSV(t *testing.T, rows ...string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "data.csv")
+	if err := os.WriteFile(filename, []byte(strings.Join(rows, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoadAbsences(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    []string
+		wantErr string
+	}{
+		{
+			name: "dates and times",
+			rows: []string{"DevName,StartTime,EndTime", "Dev1,2026-10-16,2026-10-17", "Dev2,2026-10-16 13:00,2026-10-16T17:00"},
+		},
+		{
+			name:    "bad start",
+			rows:    []string{"DevName,StartTime,EndTime", "Dev1,2026-10-16,2026-10-17", "Dev2,2026-10-16 9am,2026-10-16 17:00"},
+			wantErr: "start on row 3 for Dev2",
+		},
+		{
+			name:    "bad end",
+			rows:    []string{"DevName,StartTime,EndTime", "Dev1,2026-10-16,16/10/2026"},
+			wantErr: "end on row 2 for Dev1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := writeCSV(t, tt.rows...)
+			oncalls, err := loadOncalls(filename)
+			checkLoadError(t, "loadOncalls", err, tt.wantErr)
+			if err == nil && len(oncalls) != len(tt.rows)-1 {
+				t.Errorf("loaded %d on-calls, want %d", len(oncalls), len(tt.rows)-1)
+			}
+			leaves, err := loadLeaves(filename)
+			checkLoadError(t, "loadLeaves", err, tt.wantErr)
+			if err == nil && len(leaves) != len(tt.rows)-1 {
+				t.Errorf("loaded %d leaves, want %d", len(leaves), len(tt.rows)-1)
+			}
+		})
+	}
+}
+
+func checkLoadError(t *testing.T, load string, err error, want string) {
+	t.Helper()
+	switch {
+	case want == "" && err != nil:
+		t.Errorf("%s: %v", load, err)
+	case want != "" && (err == nil || !strings.Contains(err.Error(), want)):
+		t.Errorf("%s error = %v, want one mentioning %q", load, err, want)
+	}
+}
+
+func TestTimelineSegments(t *testing.T) {
+	tuesday := monday.AddDate(0, 0, 1)
+	none := 0.0
+	tests := []struct {
+		name  string
+		setup func(s *Scheduler)
+		want  []string // "id|start|end|content|delivered|preemptedBy" of each task item
+	}{
+		{
+			name: "preempted and resumed",
+			setup: func(s *Scheduler) {
+				s.preemptive = map[int]bool{1: true}
+				s.tasks[0].Priority = 3
+				s.tasks = append(s.tasks, &Task{Name: "Hotfix", TaskType: "Backend", Priority: 1, ParallelFactor: 1, Effort: 1, EarliestStart: tuesday})
+			},
+			want: []string{
+				"task_A_Dev1|2026-10-12T09:00:00Z|2026-10-12T17:00:00Z|Task: A (Assigned to: Dev1, preempted by Hotfix)|1|Hotfix",
+				"task_Hotfix_Dev1|2026-10-13T09:00:00Z|2026-10-13T17:00:00Z|Task: Hotfix (Assigned to: Dev1)|1|",
+				"task_A_Dev1_1|2026-10-14T09:00:00Z|2026-10-15T17:00:00Z|Task: A (Assigned to: Dev1)|2|",
+			},
+		},
+		{
+			name: "released for on-call duty",
+			setup: func(s *Scheduler) {
+				s.splitTasks = true
+				s.oncalls = []OnCall{{DevName: "Dev1", StartTime: tuesday, EndTime: tuesday, Capacity: &none}}
+			},
+			want: []string{
+				"task_A_Dev1|2026-10-12T09:00:00Z|2026-10-12T17:00:00Z|Task: A (Assigned to: Dev1, interrupted)|1|",
+				"task_A_Dev1_1|2026-10-14T09:00:00Z|2026-10-15T17:00:00Z|Task: A (Assigned to: Dev1)|2|",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newWIPScheduler(1, 0, 3)
+			tt.setup(s)
+			s.simulate(monday)
+
+			var got []string
+			for _, item := range processScheduleToTimelineData(s) {
+				if item.Kind == KindTask {
+					got = append(got, fmt.Sprintf("%s|%s|%s|%s|%v|%s", item.ID, item.Start, item.End, item.Content, item.Delivered, item.PreemptedBy))
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("task items =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseTaskTypes(t *testing.T) {
+	tests := []struct {
+		value       string
+		types       []string
+		proficiency map[string]float64
+		wantErr     bool
+	}{
+		{value: "Backend", types: []string{"Backend"}, proficiency: map[string]float64{}},
+		{value: "Backend:1.0, Frontend:0.6", types: []string{"Backend", "Frontend"}, proficiency: map[string]float64{"Backend": 1, "Frontend": 0.6}},
+		{value: "Backend,QA:0.5", types: []string{"Backend", "QA"}, proficiency: map[string]float64{"QA": 0.5}},
+		{value: "Backend:0", wantErr: true},
+		{value: "Backend:1.5", wantErr: true},
+		{value: "Backend:high", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			types, proficiency, err := parseTaskTypes(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTaskTypes(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(types, tt.types) || !reflect.DeepEqual(proficiency, tt.proficiency) {
+				t.Errorf("parseTaskTypes(%q) = %v, %v, want %v, %v", tt.value, types, proficiency, tt.types, tt.proficiency)
+			}
+		})
+	}
+}
@@ -3,39 +3,122 @@ package main
 import "time"
 
 type Task struct {
-	Name           string
-	Priority       int
-	ParallelFactor int
-	Effort         float64
-	TaskType       string
-	Dependencies   []string
-	AssignedDevs   []*Developer
-	StartTime      time.Time
-	EndTime        time.Time
-	IsCompleted    bool
-	DevStartTimes  map[string]time.Time
+	Name            string
+	Priority        int
+	ParallelFactor  int
+	Effort          float64  // RawEffort inflated by the task's overhead model
+	RawEffort       float64  // Effort as estimated, before overhead
+	Estimate        Estimate // Three-point estimate behind Effort, if given
+	TaskType        string
+	Team            string
+	MinProficiency  float64
+	AllowedDevs     []string // When set, only these developers may work on the task
+	ForbiddenDevs   []string
+	EarliestStart   time.Time
+	Parent          string
+	Dependencies    []string
+	Links           []DependencyLink // Typed links for Dependencies, finish-to-start when absent
+	AssignedDevs    []*Developer
+	StartTime       time.Time
+	EndTime         time.Time
+	DueDate         time.Time
+	IsCompleted     bool
+	InProgress      bool    // Already started before the schedule start
+	CompletedEffort float64 // Effort delivered before the schedule start
+	IsCritical      bool
+	Assignments     []*Assignment
+	Ledger          []EffortEntry // Effort delivered per developer and day, with the hours worked
+}
+
+// Assignment is one continuous stretch of a developer working on a task.
+type Assignment struct {
+	Task        string
+	Developer   string
+	Start       time.Time
+	End         time.Time // When their last work on it ended, zero while still on the task
+	Fraction    float64   // Share of the developer's capacity when they joined the task
+	Effort      float64   // Effort delivered, known once the assignment ends
+	Interrupted bool      // Taken off the task before it finished
+	PreemptedBy string    // The task that took the developer off, if any
+}
+
+// Estimate is a PERT three-point effort estimate. The zero value means the
+// task has a single fixed effort.
+type Estimate struct {
+	Optimistic  float64
+	Likely      float64
+	Pessimistic float64
+}
+
+func (e Estimate) IsZero() bool {
+	return e == Estimate{}
+}
+
+// Mean is the PERT expected effort.
+func (e Estimate) Mean() float64 {
+	return (e.Optimistic + 4*e.Likely + e.Pessimistic) / 6
+}
+
+func (e Estimate) Scale(factor float64) Estimate {
+	return Estimate{
+		Optimistic:  e.Optimistic * factor,
+		Likely:      e.Likely * factor,
+		Pessimistic: e.Pessimistic * factor,
+	}
 }
 
 type Developer struct {
 	Name         string
 	Role         string
 	TaskTypes    []string
+	Proficiency  map[string]float64 // Per task type, types missing here count as 1
+	Location     string
+	WorkWeek     WorkWeek
+	Availability []AvailabilityPeriod // Overrides the role availability, sorted by From
+	JoinDate     time.Time            // Zero if already on the team
+	ExitDate     time.Time            // Zero if not leaving
+	RampUp       []RampUpStep
+	MaxTasks     int     // Tasks worked on at once, splitting capacity evenly; 1 when unset
+	HoursPerDay  float64 // Working hours on a working day; standardHoursPerDay when unset
 	NextFreeTime time.Time
 }
 
+// RampUpStep scales a new hire's output for a number of calendar days. Steps
+// apply one after another starting from the join date.
+type RampUpStep struct {
+	Fraction float64
+	Days     int
+}
+
+// AvailabilityPeriod sets a developer's availability from a date onwards. A
+// zero From applies from the start of the schedule.
+type AvailabilityPeriod struct {
+	From    time.Time
+	Percent float64
+}
+
 type Role struct {
 	Name                string
 	AvailabilityPercent float64
+	OnCallCapacity      float64
 }
 
 type OnCall struct {
 	DevName   string
 	StartTime time.Time
 	EndTime   time.Time
+	Capacity  *float64 // nil falls back to the role's OnCallCapacity
 }
 
 type Leave struct {
 	DevName   string
 	StartTime time.Time
 	EndTime   time.Time
+	Fraction  float64 // Portion of each day taken off, 1 for a full day
+}
+
+type Holiday struct {
+	Date   time.Time
+	Name   string
+	Region string
 }
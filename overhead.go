@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Overhead model names.
+const (
+	OverheadNone   = "none"
+	OverheadLinear = "linear" // Fixed base plus a constant cost per parallel developer
+	OverheadBrooks = "brooks" // Fixed base plus a cost per pair of parallel developers
+)
+
+// OverheadModel inflates a raw effort estimate to account for coordination
+// and other overhead.
+type OverheadModel struct {
+	Model      string  `yaml:"model"`
+	Base       float64 `yaml:"base"`       // Overhead regardless of team size
+	PerDev     float64 `yaml:"perDev"`     // linear: added per parallel developer
+	PerChannel float64 `yaml:"perChannel"` // brooks: added per communication channel
+}
+
+// Factor returns the multiplier applied to raw effort for a task worked on by
+// parallel developers.
+func (m OverheadModel) Factor(parallel int) float64 {
+	p := math.Max(float64(parallel), 1)
+	switch m.Model {
+	case OverheadLinear:
+		return 1 + m.Base + m.PerDev*p
+	case OverheadBrooks:
+		return 1 + m.Base + m.PerChannel*p*(p-1)/2
+	default:
+		return 1
+	}
+}
+
+// OverheadConfig selects the overhead model for each task. A team's model
+// takes precedence over its task type's, and the default applies otherwise.
+type OverheadConfig struct {
+	Default   OverheadModel            `yaml:"default"`
+	TaskTypes map[string]OverheadModel `yaml:"taskTypes"`
+	Teams     map[string]OverheadModel `yaml:"teams"`
+}
+
+// defaultOverhead is the historical (10*parallel + 40)% increase.
+var defaultOverhead = OverheadConfig{
+	Default: OverheadModel{Model: OverheadLinear, Base: 0.4, PerDev: 0.1},
+}
+
+func (c OverheadConfig) model(task *Task) OverheadModel {
+	if m, ok := c.Teams[task.Team]; ok && task.Team != "" {
+		return m
+	}
+	if m, ok := c.TaskTypes[task.TaskType]; ok {
+		return m
+	}
+	return c.Default
+}
+
+func (c OverheadConfig) factor(task *Task) float64 {
+	return c.model(task).Factor(task.ParallelFactor)
+}
+
+// inflate sets a task's effort and estimate from its raw effort and raw
+// estimate using the task's overhead model.
+func (c OverheadConfig) inflate(task *Task, estimate Estimate) {
+	inflateBy(task, estimate, c.factor(task))
+}
+
+// inflateBy sets a task's effort and estimate from its raw effort and raw
+// estimate multiplied by factor.
+func inflateBy(task *Task, estimate Estimate, factor float64) {
+	task.Effort = roundToHour(task.RawEffort * factor)
+	task.Estimate = estimate.Scale(factor)
+}
+
+// loadOverheadConfig reads an overhead configuration from a YAML or JSON file.
+func loadOverheadConfig(filename string) (OverheadConfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return OverheadConfig{}, err
+	}
+	var config OverheadConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return OverheadConfig{}, fmt.Errorf("invalid overhead config: %v", err)
+	}
+	if config.Default == (OverheadModel{}) {
+		config.Default = defaultOverhead.Default
+	}
+
+	models := map[string]OverheadModel{"default": config.Default}
+	for name, m := range config.TaskTypes {
+		models["task type "+name] = m
+	}
+	for name, m := range config.Teams {
+		models["team "+name] = m
+	}
+	for name, m := range models {
+		switch m.Model {
+		case OverheadNone, OverheadLinear, OverheadBrooks:
+		case "":
+			return OverheadConfig{}, fmt.Errorf("overhead model for %s is missing", name)
+		default:
+			return OverheadConfig{}, fmt.Errorf("unknown overhead model %q for %s", m.Model, name)
+		}
+		if m.Base < 0 || m.PerDev < 0 || m.PerChannel < 0 {
+			return OverheadConfig{}, fmt.Errorf("overhead for %s cannot be negative", name)
+		}
+	}
+	return config, nil
+}
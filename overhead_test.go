@@ -0,0 +1,145 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOverheadModelFactor(t *testing.T) {
+	linear := OverheadModel{Model: OverheadLinear, Base: 0.4, PerDev: 0.1}
+	brooks := OverheadModel{Model: OverheadBrooks, Base: 0.2, PerChannel: 0.1}
+	tests := []struct {
+		name     string
+		model    OverheadModel
+		parallel int
+		want     float64
+	}{
+		{name: "none", model: OverheadModel{Model: OverheadNone, Base: 0.4}, parallel: 3, want: 1},
+		{name: "linear alone", model: linear, parallel: 1, want: 1.5},
+		{name: "linear in a team", model: linear, parallel: 3, want: 1.7},
+		{name: "linear with no team size counts one", model: linear, parallel: 0, want: 1.5},
+		{name: "brooks alone has no channels", model: brooks, parallel: 1, want: 1.2},
+		{name: "brooks pair", model: brooks, parallel: 2, want: 1.3},
+		{name: "brooks grows with channels", model: brooks, parallel: 4, want: 1.8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.model.Factor(tt.parallel); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Factor(%d) = %v, want %v", tt.parallel, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOverheadConfigModel(t *testing.T) {
+	config := OverheadConfig{
+		Default:   OverheadModel{Model: OverheadLinear, Base: 0.4, PerDev: 0.1},
+		TaskTypes: map[string]OverheadModel{"QA": {Model: OverheadNone}},
+		Teams:     map[string]OverheadModel{"Platform": {Model: OverheadBrooks, Base: 0.2, PerChannel: 0.1}},
+	}
+	tests := []struct {
+		name string
+		task *Task
+		want float64
+	}{
+		{name: "default", task: &Task{TaskType: "Backend", ParallelFactor: 1}, want: 1.5},
+		{name: "task type", task: &Task{TaskType: "QA", ParallelFactor: 2}, want: 1},
+		{name: "team", task: &Task{TaskType: "Backend", Team: "Platform", ParallelFactor: 2}, want: 1.3},
+		{name: "team wins over task type", task: &Task{TaskType: "QA", Team: "Platform", ParallelFactor: 2}, want: 1.3},
+		{name: "unknown team", task: &Task{TaskType: "QA", Team: "Mobile", ParallelFactor: 2}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := config.factor(tt.task); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("factor = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadOverheadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    OverheadModel // The default model
+		wantErr bool
+	}{
+		{name: "default model", yaml: "default: {model: brooks, perChannel: 0.2}", want: OverheadModel{Model: OverheadBrooks, PerChannel: 0.2}},
+		{name: "default left out", yaml: "taskTypes: {QA: {model: none}}", want: defaultOverhead.Default},
+		{name: "unknown model", yaml: "default: {model: cubic}", wantErr: true},
+		{name: "missing model", yaml: "teams: {Platform: {base: 0.2}}", wantErr: true},
+		{name: "negative overhead", yaml: "taskTypes: {QA: {model: linear, perDev: -0.1}}", wantErr: true},
+		{name: "not a mapping", yaml: "- linear", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "overhead.yaml")
+			if err := os.WriteFile(filename, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			config, err := loadOverheadConfig(filename)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if config.Default != tt.want {
+				t.Errorf("default = %+v, want %+v", config.Default, tt.want)
+			}
+		})
+	}
+}
+
+func TestSampleOverheadConfig(t *testing.T) {
+	config, err := loadOverheadConfig("overhead.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(config, defaultOverhead) {
+		t.Errorf("overhead.yaml = %+v, want the built-in overhead %+v", config, defaultOverhead)
+	}
+}
+
+func TestSubtaskOverhead(t *testing.T) {
+	// A Backend parent of two developers takes 1.6, QA on its own takes 1
+	overhead := OverheadConfig{
+		Default:   defaultOverhead.Default,
+		TaskTypes: map[string]OverheadModel{"QA": {Model: OverheadNone}},
+	}
+	tests := []struct {
+		name   string
+		own    bool
+		effort float64
+		likely float64
+	}{
+		{name: "parent's factor", effort: 3.25, likely: 3.2}, // Effort rounded to whole hours
+		{name: "own overhead", own: true, effort: 2, likely: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := []SubtaskRule{{Name: "QA", Column: "NeedsQA", TaskType: "QA", EffortRatio: 0.25, OwnOverhead: tt.own}}
+			parent := &Task{Name: "A", TaskType: "Backend", ParallelFactor: 2, RawEffort: 8}
+			estimate := Estimate{Optimistic: 4, Likely: 8, Pessimistic: 12}
+			overhead.inflate(parent, estimate)
+			subtasks, err := deriveSubtasks(parent, estimate, rules, overhead, []string{"A", "Backend", "1", "8", "2", "", "", "true"}, builtinColumns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(subtasks) != 1 {
+				t.Fatalf("%d subtasks, want 1", len(subtasks))
+			}
+			qa := subtasks[0]
+			if qa.RawEffort != 2 || qa.Effort != tt.effort {
+				t.Errorf("raw effort, effort = %v, %v, want 2, %v", qa.RawEffort, qa.Effort, tt.effort)
+			}
+			if math.Abs(qa.Estimate.Likely-tt.likely) > 1e-9 {
+				t.Errorf("likely estimate = %v, want %v", qa.Estimate.Likely, tt.likely)
+			}
+		})
+	}
+}
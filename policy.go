@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// AssignmentPolicy makes the individual decisions of the day-by-day
+// assignment loop: which task is considered first, which of the free
+// developers a task prefers, and how many of them it takes.
+type AssignmentPolicy interface {
+	// OrderTasks arranges s.tasks in the order they are considered.
+	OrderTasks(s *Scheduler, startDate time.Time)
+	// RankDevelopers orders the free developers for a task, most preferred
+	// first.
+	RankDevelopers(s *Scheduler, task *Task, devs []*Developer, date time.Time) []*Developer
+	// SlotsToFill returns how many of the ranked developers to assign.
+	SlotsToFill(s *Scheduler, task *Task, devs []*Developer, remainingSlots int) int
+}
+
+func newAssignmentPolicy(name string) (AssignmentPolicy, error) {
+	switch name {
+	case "", "priority":
+		return priorityPolicy{}, nil
+	case "edf":
+		return earliestDeadlinePolicy{}, nil
+	case "sjf":
+		return shortestJobPolicy{}, nil
+	case "critical-path":
+		return criticalPathPolicy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown assignment policy %q", name)
+	}
+}
+
+// priorityPolicy is strict priority order, taking developers in the order
+// they appear in developers.csv.
+type priorityPolicy struct {
+	csvOrderDevelopers
+	fillAllSlots
+}
+
+func (priorityPolicy) OrderTasks(s *Scheduler, startDate time.Time) {
+	s.sortTasksByPriority()
+}
+
+// earliestDeadlinePolicy considers tasks with the nearest due date first.
+// Tasks without a due date follow in priority order.
+type earliestDeadlinePolicy struct {
+	fastestDevelopers
+	fillAllSlots
+}
+
+func (earliestDeadlinePolicy) OrderTasks(s *Scheduler, startDate time.Time) {
+	sort.SliceStable(s.tasks, func(i, j int) bool {
+		a, b := s.tasks[i], s.tasks[j]
+		if a.DueDate.IsZero() != b.DueDate.IsZero() {
+			return !a.DueDate.IsZero()
+		}
+		if !a.DueDate.Equal(b.DueDate) {
+			return a.DueDate.Before(b.DueDate)
+		}
+		return a.Priority < b.Priority
+	})
+}
+
+// shortestJobPolicy considers the tasks with the least effort first.
+type shortestJobPolicy struct {
+	fastestDevelopers
+	fillAllSlots
+}
+
+func (shortestJobPolicy) OrderTasks(s *Scheduler, startDate time.Time) {
+	sort.SliceStable(s.tasks, func(i, j int) bool {
+		a, b := s.tasks[i], s.tasks[j]
+		if a.Effort != b.Effort {
+			return a.Effort < b.Effort
+		}
+		return a.Priority < b.Priority
+	})
+}
+
+// criticalPathPolicy considers first the tasks with the longest estimated
+// chain of work still depending on them.
+type criticalPathPolicy struct {
+	fastestDevelopers
+	fillAllSlots
+}
+
+func (criticalPathPolicy) OrderTasks(s *Scheduler, startDate time.Time) {
+	tails := s.remainingChainWorkdays()
+	sort.SliceStable(s.tasks, func(i, j int) bool {
+		a, b := s.tasks[i], s.tasks[j]
+		if tails[a.Name] != tails[b.Name] {
+			return tails[a.Name] > tails[b.Name]
+		}
+		return a.Priority < b.Priority
+	})
+}
+
+// remainingChainWorkdays estimates, for every task, the working days from its
+// start to the end of the longest chain of dependents.
+func (s *Scheduler) remainingChainWorkdays() map[string]int {
+	dependents := make(map[string][]*Task)
+	for _, task := range s.tasks {
+		for _, depName := range task.Dependencies {
+			dependents[depName] = append(dependents[depName], task)
+		}
+	}
+
+	tails := make(map[string]int)
+	var resolve func(task *Task) int
+	resolve = func(task *Task) int {
+		if tail, ok := tails[task.Name]; ok {
+			return tail
+		}
+		longest := 0
+		for _, dependent := range dependents[task.Name] {
+			longest = max(longest, resolve(dependent))
+		}
+		tails[task.Name] = s.estimateWorkdays(task) + longest
+		return tails[task.Name]
+	}
+
+	for _, task := range s.tasks {
+		resolve(task)
+	}
+	return tails
+}
+
+// csvOrderDevelopers keeps free developers in developers.csv order.
+type csvOrderDevelopers struct{}
+
+func (csvOrderDevelopers) RankDevelopers(s *Scheduler, task *Task, devs []*Developer, date time.Time) []*Developer {
+	return devs
+}
+
+// fastestDevelopers prefers the developers who would make the most daily
+// progress on the task.
+type fastestDevelopers struct{}
+
+func (fastestDevelopers) RankDevelopers(s *Scheduler, task *Task, devs []*Developer, date time.Time) []*Developer {
+	ranked := append([]*Developer(nil), devs...)
+	rate := make(map[string]float64, len(ranked))
+	for _, dev := range ranked {
+		rate[dev.Name] = s.calculateDailyProgress(task, []*Developer{dev}, date)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return rate[ranked[i].Name] > rate[ranked[j].Name]
+	})
+	return ranked
+}
+
+// fillAllSlots assigns as many free developers as the task has open slots.
+type fillAllSlots struct{}
+
+func (fillAllSlots) SlotsToFill(s *Scheduler, task *Task, devs []*Developer, remainingSlots int) int {
+	return min(len(devs), remainingSlots)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPolicyTaskOrder(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   []string
+	}{
+		{policy: "", want: []string{"A", "B", "C", "D"}},
+		{policy: "priority", want: []string{"A", "B", "C", "D"}},
+		{policy: "edf", want: []string{"C", "B", "A", "D"}},
+		{policy: "sjf", want: []string{"B", "D", "C", "A"}},
+		{policy: "critical-path", want: []string{"D", "A", "C", "B"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			tasks := []*Task{
+				{Name: "D", Priority: 4, Effort: 2, ParallelFactor: 1},
+				{Name: "C", Priority: 3, Effort: 3, ParallelFactor: 1, DueDate: monday.AddDate(0, 0, 7)},
+				{Name: "B", Priority: 2, Effort: 1, ParallelFactor: 1, DueDate: monday.AddDate(0, 0, 14)},
+				{Name: "A", Priority: 1, Effort: 5, ParallelFactor: 1, Dependencies: []string{"D"}},
+			}
+			s := NewScheduler(tasks, nil, nil, nil, nil, nil)
+			s.quiet = true
+			policy, err := newAssignmentPolicy(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			policy.OrderTasks(s, monday)
+
+			var got []string
+			for _, task := range s.tasks {
+				got = append(got, task.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := newAssignmentPolicy("fifo"); err == nil {
+		t.Errorf("newAssignmentPolicy(%q) = nil error, want error", "fifo")
+	}
+}
+
+func TestPolicyRankDevelopers(t *testing.T) {
+	devs := []*Developer{
+		{Name: "Dev1", Role: "Senior", TaskTypes: []string{"Backend"}, Proficiency: map[string]float64{"Backend": 0.5}},
+		{Name: "Dev2", Role: "Junior", TaskTypes: []string{"Backend"}},
+		{Name: "Dev3", Role: "Senior", TaskTypes: []string{"Backend"}},
+	}
+	roles := map[string]*Role{
+		"Senior": {Name: "Senior", AvailabilityPercent: 1},
+		"Junior": {Name: "Junior", AvailabilityPercent: 0.8},
+	}
+	task := &Task{Name: "A", TaskType: "Backend"}
+	tests := []struct {
+		policy string
+		want   []string
+	}{
+		{policy: "priority", want: []string{"Dev1", "Dev2", "Dev3"}},
+		{policy: "sjf", want: []string{"Dev3", "Dev2", "Dev1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			s := NewScheduler([]*Task{task}, devs, roles, nil, nil, nil)
+			s.quiet = true
+			policy, err := newAssignmentPolicy(tt.policy)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, dev := range policy.RankDevelopers(s, task, devs, monday) {
+				got = append(got, dev.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ranking = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
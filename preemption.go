@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parsePreemptivePriorities parses a comma separated list of priority levels
+// whose tasks may preempt lower-priority work.
+func parsePreemptivePriorities(value string) (map[int]bool, error) {
+	priorities := make(map[int]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		priority, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid preemptive priority %q", part)
+		}
+		priorities[priority] = true
+	}
+	return priorities, nil
+}
+
+// preemptFor fills the open slots of an urgent task with developers taken off
+// lower-priority tasks in progress, interrupting the lowest priority work
+// first.
+func (s *Scheduler) preemptFor(task *Task, date time.Time) {
+	if date.Before(task.EarliestStart) && !sameDay(date, task.EarliestStart) {
+		return
+	}
+
+	type candidate struct {
+		dev    *Developer
+		victim *Task
+	}
+	var candidates []candidate
+	notBefore := s.startNotBefore(task, date)
+	for _, dev := range s.developers {
+		if task.isAssigned(dev.Name) {
+			continue
+		}
+		if !s.canDevWorkOnTask(dev, task) || !s.isDevWorking(dev, date) {
+			continue
+		}
+		// Leave them on their work until the day they can start on the task
+		if hoursFrom(s.workingHours(dev, date), notBefore) <= 0 {
+			continue
+		}
+		if victim := s.preemptibleTask(dev, task, date); victim != nil {
+			candidates = append(candidates, candidate{dev, victim})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].victim.Priority > candidates[j].victim.Priority
+	})
+
+	open := task.ParallelFactor - len(task.AssignedDevs)
+	var freed []*Developer
+	for _, c := range candidates[:min(open, len(candidates))] {
+		s.preempt(c.victim, c.dev, task, date)
+		freed = append(freed, c.dev)
+	}
+	if len(freed) > 0 {
+		s.assignDevsToTask(task, freed, date)
+	}
+}
+
+// preemptibleTask returns the lowest priority task a developer could be taken
+// off for an urgent task, or nil when they are not blocked by such work.
+func (s *Scheduler) preemptibleTask(dev *Developer, urgent *Task, date time.Time) *Task {
+	if s.hasFreeSlot(dev, date) {
+		return nil
+	}
+	var victim *Task
+	for _, task := range s.tasks {
+		if !task.isAssigned(dev.Name) || task == urgent {
+			continue
+		}
+		if task.IsCompleted || task.remainingEffort() <= effortEpsilon || task.Priority <= urgent.Priority {
+			continue
+		}
+		if victim == nil || task.Priority > victim.Priority {
+			victim = task
+		}
+	}
+	return victim
+}
+
+// preempt takes dev off victim from date on to work on by.
+func (s *Scheduler) preempt(victim *Task, dev *Developer, by *Task, date time.Time) {
+	from := s.startNotBefore(by, date)
+	s.debug("Task %s preempts %s on %s from %v", by.Name, dev.Name, victim.Name, from)
+	s.takeOffTask(victim, dev, from, by.Name)
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPreemptionWaitsForDependencies(t *testing.T) {
+	tests := []struct {
+		name     string
+		release  float64 // Effort of the dependency, finished by Dev2 on Monday
+		refactor []string
+		hotfix   []string
+	}{
+		{
+			// Dev1 keeps working on the refactor on Monday and only
+			// switches to the hotfix on Tuesday
+			name:     "dependency finishing at the end of the day",
+			release:  1,
+			refactor: []string{"10-12 09:00-17:00"},
+			hotfix:   []string{"10-13 09:00-17:00"},
+		},
+		{
+			// Dev1 works on the refactor until the hotfix can start
+			name:     "dependency finishing mid-day",
+			release:  0.5,
+			refactor: []string{"10-12 09:00-13:00"},
+			hotfix:   []string{"10-12 13:00-17:00", "10-13 09:00-13:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := []*Task{
+				{Name: "Release", TaskType: "QA", Priority: 2, ParallelFactor: 1, Effort: tt.release},
+				{Name: "Refactor", TaskType: "Backend", Priority: 3, ParallelFactor: 1, Effort: 5},
+				{Name: "Hotfix", TaskType: "Backend", Priority: 1, ParallelFactor: 1, Effort: 1,
+					Dependencies: []string{"Release"}},
+			}
+			devs := []*Developer{
+				{Name: "Dev1", Role: "Senior", TaskTypes: []string{"Backend"}, MaxTasks: 1},
+				{Name: "Dev2", Role: "Senior", TaskTypes: []string{"QA"}, MaxTasks: 1},
+			}
+			roles := map[string]*Role{"Senior": {Name: "Senior", AvailabilityPercent: 1}}
+			s := NewScheduler(tasks, devs, roles, nil, nil, nil)
+			s.quiet = true
+			s.preemptive = map[int]bool{1: true}
+			s.simulate(monday)
+
+			refactor, hotfix := tasks[1], tasks[2]
+			if got := ledgerHours(refactor, monday, monday); !reflect.DeepEqual(got, tt.refactor) {
+				t.Errorf("refactor work on Monday = %v, want %v", got, tt.refactor)
+			}
+			if got := ledgerHours(hotfix, monday, monday.AddDate(0, 0, 1)); !reflect.DeepEqual(got, tt.hotfix) {
+				t.Errorf("hotfix work = %v, want %v", got, tt.hotfix)
+			}
+			if len(refactor.Assignments) == 0 || refactor.Assignments[0].PreemptedBy != "Hotfix" {
+				t.Errorf("Dev1 was not taken off the refactor for the hotfix")
+			}
+		})
+	}
+}
+
+// ledgerHours lists when a task was worked on between two days, as
+// "01-02 15:04-15:04".
+func ledgerHours(task *Task, from, to time.Time) []string {
+	var hours []string
+	for _, entry := range task.Ledger {
+		if !entry.Date.Before(from) && !entry.Date.After(to) {
+			hours = append(hours, entry.Start.Format("01-02 15:04-")+entry.End.Format("15:04"))
+		}
+	}
+	return hours
+}
+
+func TestPreemptionPausesVictim(t *testing.T) {
+	tuesday := monday.AddDate(0, 0, 1)
+	tests := []struct {
+		name     string
+		devs     []string
+		parallel int
+		effort   float64
+		ledger   []string // Refactor's ledger, "Dev 01-02"
+		resumed  bool     // Dev1 is put back on the refactor after the hotfix
+	}{
+		{
+			name:     "victim left without developers waits and resumes",
+			devs:     []string{"Dev1"},
+			parallel: 1,
+			effort:   3,
+			ledger:   []string{"Dev1 10-12", "Dev1 10-14", "Dev1 10-15"},
+			resumed:  true,
+		},
+		{
+			name:     "victim carries on with its other developer",
+			devs:     []string{"Dev1", "Dev2"},
+			parallel: 2,
+			effort:   4,
+			ledger:   []string{"Dev1 10-12", "Dev2 10-12", "Dev2 10-13", "Dev2 10-14"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refactor := &Task{Name: "Refactor", TaskType: "Backend", Priority: 3, ParallelFactor: tt.parallel, Effort: tt.effort}
+			hotfix := &Task{Name: "Hotfix", TaskType: "Backend", Priority: 1, ParallelFactor: 1, Effort: 1, EarliestStart: tuesday}
+			var devs []*Developer
+			for _, name := range tt.devs {
+				devs = append(devs, &Developer{Name: name, Role: "Senior", TaskTypes: []string{"Backend"}})
+			}
+			roles := map[string]*Role{"Senior": {Name: "Senior", AvailabilityPercent: 1}}
+			s := NewScheduler([]*Task{refactor, hotfix}, devs, roles, nil, nil, nil)
+			s.quiet = true
+			s.preemptive = map[int]bool{1: true}
+			s.simulate(monday)
+
+			var ledger []string
+			for _, entry := range refactor.Ledger {
+				ledger = append(ledger, entry.Developer+entry.Date.Format(" 01-02"))
+			}
+			if !reflect.DeepEqual(ledger, tt.ledger) {
+				t.Errorf("refactor ledger = %v, want %v", ledger, tt.ledger)
+			}
+			if !refactor.IsCompleted || refactor.remainingEffort() > effortEpsilon {
+				t.Errorf("refactor not finished, %v effort left", refactor.remainingEffort())
+			}
+			if len(hotfix.Ledger) != 1 || hotfix.Ledger[0].Developer != "Dev1" || !sameDay(hotfix.Ledger[0].Date, tuesday) {
+				t.Errorf("hotfix ledger = %v, want Dev1 on Tuesday", hotfix.Ledger)
+			}
+
+			var dev1 []*Assignment
+			for _, a := range refactor.Assignments {
+				if a.Developer == "Dev1" {
+					dev1 = append(dev1, a)
+				}
+			}
+			if len(dev1) == 0 || !dev1[0].Interrupted || dev1[0].PreemptedBy != "Hotfix" || !sameDay(dev1[0].End, monday) {
+				t.Fatalf("Dev1 was not taken off the refactor for the hotfix after Monday")
+			}
+			if resumed := len(dev1) > 1; resumed != tt.resumed {
+				t.Errorf("Dev1 resumed the refactor = %v, want %v", resumed, tt.resumed)
+			}
+		})
+	}
+}
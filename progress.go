@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// TaskProgress records the actual state of a task when replanning mid-flight.
+type TaskProgress struct {
+	Task            string
+	ActualStart     time.Time
+	ActualEnd       time.Time // Optional, only meaningful once complete
+	PercentComplete float64
+	RemainingEffort *float64 // Takes precedence over PercentComplete
+	Developers      []string
+}
+
+// remaining returns the effort left on a task of the given total effort.
+func (p TaskProgress) remaining(effort float64) float64 {
+	if p.RemainingEffort != nil {
+		return *p.RemainingEffort
+	}
+	return effort * (1 - p.PercentComplete/100)
+}
+
+// validateProgress rejects progress rows for unknown tasks or developers.
+func validateProgress(progress []TaskProgress, tasks []*Task, developers []*Developer) error {
+	knownTasks := make(map[string]bool)
+	for _, task := range tasks {
+		knownTasks[task.Name] = true
+	}
+	knownDevs := make(map[string]bool)
+	for _, dev := range developers {
+		knownDevs[dev.Name] = true
+	}
+
+	for _, p := range progress {
+		if !knownTasks[p.Task] {
+			return fmt.Errorf("progress references unknown task %q", p.Task)
+		}
+		for _, devName := range p.Developers {
+			if !knownDevs[devName] {
+				return fmt.Errorf("progress for task %s references unknown developer %q", p.Task, devName)
+			}
+		}
+	}
+	return nil
+}
+
+// applyProgress seeds the schedule with the uploaded progress. Finished tasks
+// are marked completed, and tasks in flight stay pinned to the developers
+// already working on them, with only their remaining effort planned from
+// startDate.
+func (s *Scheduler) applyProgress(startDate time.Time) {
+	for _, p := range s.progress {
+		task := s.findTask(p.Task)
+		if task == nil {
+			continue // Filtered out for lack of a matching developer
+		}
+
+		var devs []*Developer
+		for _, devName := range p.Developers {
+			if dev := s.findDeveloper(devName); dev != nil {
+				devs = append(devs, dev)
+			}
+		}
+
+		remaining := p.remaining(task.Effort)
+		if remaining > 0 && len(devs) == 0 {
+			// Started but nobody to pin it to, so plan the rest normally
+			task.InProgress = true
+			task.CompletedEffort = task.Effort - remaining
+			continue
+		}
+
+		start := p.ActualStart
+		if start.IsZero() {
+			start = startDate
+		}
+		// Effort delivered so far is split evenly across the developers
+		delivered := (task.Effort - math.Max(remaining, 0)) / float64(len(devs))
+		task.AssignedDevs = devs
+		for _, dev := range devs {
+			task.startAssignment(dev.Name, start, 1, delivered)
+		}
+		task.StartTime = start
+
+		if remaining <= 0 {
+			task.IsCompleted = true
+			task.EndTime = p.ActualEnd
+			if task.EndTime.IsZero() {
+				task.EndTime = startDate.AddDate(0, 0, -1)
+			}
+			task.endAssignments()
+			s.debug("Task %s already completed on %v", task.Name, task.EndTime)
+			continue
+		}
+
+		task.InProgress = true
+		task.CompletedEffort = task.Effort - remaining
+		task.EndTime = s.calculateEndDate(task, devs, startDate, remaining)
+		for _, dev := range devs {
+			if task.EndTime.After(dev.NextFreeTime) {
+				dev.NextFreeTime = task.EndTime
+			}
+		}
+		s.debug("Task %s in progress with %.2f effort remaining, pinned until %v", task.Name, remaining, task.EndTime)
+	}
+}
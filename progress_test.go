@@ -0,0 +1,122 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApplyProgress(t *testing.T) {
+	friday := monday.AddDate(0, 0, -3)
+	ptr := func(v float64) *float64 { return &v }
+	tests := []struct {
+		name            string
+		progress        TaskProgress
+		completed       bool
+		inProgress      bool
+		completedEffort float64
+		end             time.Time // Only checked for completed tasks
+		devs            []string  // Developers pinned to the task
+		delivered       float64   // Effort each of them is credited with
+	}{
+		{
+			name:       "in flight pinned to its developers",
+			progress:   TaskProgress{Task: "A", ActualStart: friday, PercentComplete: 50, Developers: []string{"Dev1", "Dev2"}},
+			inProgress: true, completedEffort: 2,
+			devs: []string{"Dev1", "Dev2"}, delivered: 1,
+		},
+		{
+			name:       "remaining effort wins over percent complete",
+			progress:   TaskProgress{Task: "A", PercentComplete: 50, RemainingEffort: ptr(3), Developers: []string{"Dev1"}},
+			inProgress: true, completedEffort: 1,
+			devs: []string{"Dev1"}, delivered: 1,
+		},
+		{
+			name:       "in flight without developers is planned normally",
+			progress:   TaskProgress{Task: "A", PercentComplete: 25},
+			inProgress: true, completedEffort: 1,
+		},
+		{
+			name:      "finished at its actual end",
+			progress:  TaskProgress{Task: "A", ActualStart: friday, ActualEnd: friday.Add(15 * time.Hour), PercentComplete: 100, Developers: []string{"Dev1"}},
+			completed: true, end: friday.Add(15 * time.Hour),
+			devs: []string{"Dev1"}, delivered: 4,
+		},
+		{
+			// With no dates there is no stretch of work to show for it
+			name:      "finished without dates ends the day before the start",
+			progress:  TaskProgress{Task: "A", RemainingEffort: ptr(0), PercentComplete: 10, Developers: []string{"Dev1"}},
+			completed: true, end: monday.AddDate(0, 0, -1),
+		},
+		{
+			name:      "overrun counts as finished",
+			progress:  TaskProgress{Task: "A", ActualStart: friday, RemainingEffort: ptr(-1), Developers: []string{"Dev1", "Dev2"}},
+			completed: true, end: monday.AddDate(0, 0, -1),
+			devs: []string{"Dev1", "Dev2"}, delivered: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newPinningScheduler()
+			s.progress = []TaskProgress{tt.progress}
+			s.applyProgress(monday)
+			task := s.findTask("A")
+
+			if task.IsCompleted != tt.completed || task.InProgress != tt.inProgress {
+				t.Errorf("completed, in progress = %v, %v, want %v, %v", task.IsCompleted, task.InProgress, tt.completed, tt.inProgress)
+			}
+			if tt.inProgress && task.CompletedEffort != tt.completedEffort {
+				t.Errorf("completed effort = %v, want %v", task.CompletedEffort, tt.completedEffort)
+			}
+			if tt.completed && !task.EndTime.Equal(tt.end) {
+				t.Errorf("end = %v, want %v", task.EndTime, tt.end)
+			}
+
+			var devs []string
+			for _, a := range task.Assignments {
+				devs = append(devs, a.Developer)
+				if a.Effort != tt.delivered {
+					t.Errorf("%s credited with %v, want %v", a.Developer, a.Effort, tt.delivered)
+				}
+				if tt.completed && a.End.IsZero() {
+					t.Errorf("%s still on the finished task", a.Developer)
+				}
+			}
+			if !reflect.DeepEqual(devs, tt.devs) {
+				t.Errorf("pinned developers = %v, want %v", devs, tt.devs)
+			}
+		})
+	}
+}
+
+func TestProgressPinsDevelopers(t *testing.T) {
+	s := newPinningScheduler()
+	s.findTask("A").ParallelFactor = 1 // Dev1 would be picked if A were free
+	s.progress = []TaskProgress{{Task: "A", PercentComplete: 50, Developers: []string{"Dev2"}}}
+	s.simulate(monday)
+
+	a := s.findTask("A")
+	for _, entry := range a.Ledger {
+		if entry.Developer != "Dev2" {
+			t.Errorf("%s worked on A, want only the pinned Dev2", entry.Developer)
+		}
+	}
+	if want := time.Date(2026, 10, 13, 17, 0, 0, 0, time.UTC); !a.EndTime.Equal(want) {
+		t.Errorf("A end = %v, want %v", a.EndTime, want)
+	}
+}
+
+// newPinningScheduler returns a scheduler with a 4-day task A and two
+// developers who could both work on it.
+func newPinningScheduler() *Scheduler {
+	tasks := []*Task{{Name: "A", TaskType: "Backend", Priority: 1, ParallelFactor: 2, Effort: 4}}
+	devs := []*Developer{
+		{Name: "Dev1", Role: "Senior", TaskTypes: []string{"Backend"}},
+		{Name: "Dev2", Role: "Senior", TaskTypes: []string{"Backend"}},
+	}
+	roles := map[string]*Role{"Senior": {Name: "Senior", AvailabilityPercent: 1}}
+	s := NewScheduler(tasks, devs, roles, nil, nil, nil)
+	s.quiet = true
+	return s
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scenario patch operations.
+const (
+	PatchAddDeveloper  = "add-developer"
+	PatchDeveloperExit = "developer-exit"
+	PatchDropTask      = "drop-task"
+	PatchSetParallel   = "set-parallel"
+	PatchSetEffort     = "set-effort"
+)
+
+// ScenarioPatch is a single change applied to the baseline inputs. Which
+// fields are used depends on Op.
+type ScenarioPatch struct {
+	Op        string   `json:"op"`
+	Developer string   `json:"developer,omitempty"`
+	Role      string   `json:"role,omitempty"`
+	TaskTypes []string `json:"taskTypes,omitempty"`
+	Task      string   `json:"task,omitempty"`
+	Date      string   `json:"date,omitempty"`
+	Value     float64  `json:"value,omitempty"`
+}
+
+type Scenario struct {
+	Name    string          `json:"name"`
+	Patches []ScenarioPatch `json:"patches"`
+}
+
+type AssignmentChange struct {
+	Task     string   `json:"task"`
+	Baseline []string `json:"baseline"`
+	Scenario []string `json:"scenario"`
+}
+
+type ScenarioResult struct {
+	Name               string             `json:"name"`
+	FinishDate         string             `json:"finishDate"`
+	FinishDeltaDays    int                `json:"finishDeltaWorkdays"`
+	TaskFinishDates    map[string]string  `json:"taskFinishDates"`
+	Utilization        map[string]float64 `json:"utilization"`
+	ChangedAssignments []AssignmentChange `json:"changedAssignments"`
+}
+
+type ScenarioComparison struct {
+	Baseline  ScenarioResult   `json:"baseline"`
+	Scenarios []ScenarioResult `json:"scenarios"`
+}
+
+// maxScenarios is how many scenarios one comparison may hold. Each is a full
+// run of the configured strategy, which for optimize means
+// annealingIterations simulations.
+const maxScenarios = 10
+
+// CompareScenarios schedules the baseline and every scenario concurrently on
+// independent copies of the scheduler and compares each against the baseline.
+func (s *Scheduler) CompareScenarios(startDate time.Time, scenarios []Scenario) (*ScenarioComparison, error) {
+	if len(scenarios) > maxScenarios {
+		return nil, fmt.Errorf("at most %d scenarios can be compared at once, got %d", maxScenarios, len(scenarios))
+	}
+
+	trials := make([]*Scheduler, len(scenarios)+1)
+	trials[0] = s.clone()
+	for i, scenario := range scenarios {
+		trial := s.clone()
+		for _, patch := range scenario.Patches {
+			if err := trial.applyPatch(patch); err != nil {
+				return nil, fmt.Errorf("scenario %q: %v", scenario.Name, err)
+			}
+		}
+		trials[i+1] = trial
+	}
+
+	var wg sync.WaitGroup
+	for _, trial := range trials {
+		wg.Add(1)
+		go func(trial *Scheduler) {
+			defer wg.Done()
+			trial.strategy.Schedule(trial, startDate)
+		}(trial)
+	}
+	wg.Wait()
+
+	baseline := trials[0]
+	comparison := &ScenarioComparison{
+		Baseline:  baseline.scenarioResult("baseline", startDate, baseline),
+		Scenarios: make([]ScenarioResult, len(scenarios)),
+	}
+	for i, scenario := range scenarios {
+		comparison.Scenarios[i] = trials[i+1].scenarioResult(scenario.Name, startDate, baseline)
+	}
+	return comparison, nil
+}
+
+func (s *Scheduler) applyPatch(patch ScenarioPatch) error {
+	switch patch.Op {
+	case PatchAddDeveloper:
+		if patch.Developer == "" || patch.Role == "" || len(patch.TaskTypes) == 0 {
+			return fmt.Errorf("%s needs developer, role and taskTypes", patch.Op)
+		}
+		if s.findDeveloper(patch.Developer) != nil {
+			return fmt.Errorf("developer %s already exists", patch.Developer)
+		}
+		s.developers = append(s.developers, &Developer{
+			Name:      patch.Developer,
+			Role:      patch.Role,
+			TaskTypes: patch.TaskTypes,
+		})
+
+	case PatchDeveloperExit:
+		dev := s.findDeveloper(patch.Developer)
+		if dev == nil {
+			return fmt.Errorf("unknown developer %q", patch.Developer)
+		}
+		date, err := time.Parse("2006-01-02", patch.Date)
+		if err != nil {
+			return fmt.Errorf("invalid date %q for %s: %v", patch.Date, patch.Op, err)
+		}
+		dev.ExitDate = date
+
+	case PatchDropTask:
+		if s.findTask(patch.Task) == nil {
+			return fmt.Errorf("unknown task %q", patch.Task)
+		}
+		dropped := map[string]bool{patch.Task: true}
+		var kept []*Task
+		for _, task := range s.tasks {
+			if task.Name == patch.Task || task.Parent == patch.Task {
+				dropped[task.Name] = true
+				continue
+			}
+			kept = append(kept, task)
+		}
+		for _, task := range kept {
+			var deps []string
+			for _, depName := range task.Dependencies {
+				if !dropped[depName] {
+					deps = append(deps, depName)
+				}
+			}
+			var links []DependencyLink
+			for _, link := range task.Links {
+				if !dropped[link.Task] {
+					links = append(links, link)
+				}
+			}
+			task.Dependencies = deps
+			task.Links = links
+		}
+		s.tasks = kept
+
+	case PatchSetParallel:
+		task := s.findTask(patch.Task)
+		if task == nil {
+			return fmt.Errorf("unknown task %q", patch.Task)
+		}
+		if patch.Value < 1 || patch.Value != math.Trunc(patch.Value) {
+			return fmt.Errorf("%s value must be a whole number of at least 1", patch.Op)
+		}
+		// Re-apply the overhead model for the new team size
+		estimate := task.Estimate.Scale(1 / s.overheadFactor(task))
+		task.ParallelFactor = int(patch.Value)
+		s.reinflate(task, estimate)
+
+	case PatchSetEffort:
+		task := s.findTask(patch.Task)
+		if task == nil {
+			return fmt.Errorf("unknown task %q", patch.Task)
+		}
+		if patch.Value <= 0 {
+			return fmt.Errorf("%s value must be positive", patch.Op)
+		}
+		task.RawEffort = patch.Value
+		s.reinflate(task, Estimate{})
+
+	default:
+		return fmt.Errorf("unknown patch op %q", patch.Op)
+	}
+	return nil
+}
+
+// subtaskRule returns the rule a subtask was derived by, or nil for a task
+// read from tasks.csv.
+func (s *Scheduler) subtaskRule(task *Task) *SubtaskRule {
+	if task.Parent == "" {
+		return nil
+	}
+	for i, rule := range s.subtaskRules {
+		if task.Name == task.Parent+"_"+rule.Name {
+			return &s.subtaskRules[i]
+		}
+	}
+	return nil
+}
+
+// overheadFactor returns the factor a task's raw effort is inflated by, the
+// way it was when the task was loaded.
+func (s *Scheduler) overheadFactor(task *Task) float64 {
+	if rule := s.subtaskRule(task); rule != nil {
+		if parent := s.findTask(task.Parent); parent != nil {
+			return rule.overheadFactor(s.overhead, parent, task)
+		}
+	}
+	return s.overhead.factor(task)
+}
+
+// reinflate sets a patched task's effort from its raw effort and raw
+// estimate, and re-derives the effort of the subtasks split off it.
+func (s *Scheduler) reinflate(task *Task, estimate Estimate) {
+	inflateBy(task, estimate, s.overheadFactor(task))
+	for _, subtask := range s.tasks {
+		rule := s.subtaskRule(subtask)
+		if rule == nil || subtask.Parent != task.Name {
+			continue
+		}
+		subtask.RawEffort = task.RawEffort * rule.EffortRatio
+		inflateBy(subtask, estimate.Scale(rule.EffortRatio), s.overheadFactor(subtask))
+	}
+}
+
+func (s *Scheduler) findDeveloper(name string) *Developer {
+	for _, dev := range s.developers {
+		if dev.Name == name {
+			return dev
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) findTask(name string) *Task {
+	for _, task := range s.tasks {
+		if task.Name == name {
+			return task
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) scenarioResult(name string, startDate time.Time, baseline *Scheduler) ScenarioResult {
+	finish := s.projectEnd()
+	result := ScenarioResult{
+		Name:               name,
+		TaskFinishDates:    make(map[string]string),
+		Utilization:        s.developerUtilization(startDate, finish),
+		ChangedAssignments: []AssignmentChange{},
+	}
+	if !finish.IsZero() {
+		result.FinishDate = finish.Format("2006-01-02")
+	}
+
+	baselineFinish := baseline.projectEnd()
+	switch {
+	case finish.After(baselineFinish):
+		result.FinishDeltaDays = s.workdaysBetween(baselineFinish.AddDate(0, 0, 1), finish)
+	case finish.Before(baselineFinish):
+		result.FinishDeltaDays = -s.workdaysBetween(finish.AddDate(0, 0, 1), baselineFinish)
+	}
+
+	baselineDevs := baseline.assignedDeveloperNames()
+	for _, task := range s.tasks {
+		if !task.IsCompleted {
+			continue
+		}
+		result.TaskFinishDates[task.Name] = task.EndTime.Format("2006-01-02")
+
+		devs := assignedNames(task)
+		if base, ok := baselineDevs[task.Name]; ok && strings.Join(base, ",") != strings.Join(devs, ",") {
+			result.ChangedAssignments = append(result.ChangedAssignments, AssignmentChange{
+				Task:     task.Name,
+				Baseline: base,
+				Scenario: devs,
+			})
+		}
+	}
+	return result
+}
+
+// projectEnd returns the latest end date of any completed task.
+func (s *Scheduler) projectEnd() time.Time {
+	var end time.Time
+	for _, task := range s.tasks {
+		if task.IsCompleted && task.EndTime.After(end) {
+			end = task.EndTime
+		}
+	}
+	return end
+}
+
+func (s *Scheduler) assignedDeveloperNames() map[string][]string {
+	names := make(map[string][]string)
+	for _, task := range s.tasks {
+		if task.IsCompleted {
+			names[task.Name] = assignedNames(task)
+		}
+	}
+	return names
+}
+
+// assignedNames lists everyone who worked on the task.
+func assignedNames(task *Task) []string {
+	var names []string
+	for _, a := range task.Assignments {
+		if !containsString(names, a.Developer) {
+			names = append(names, a.Developer)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// developerUtilization returns, per developer, the share of their working
+// days between start and end that they spend assigned to tasks, counting the
+// allocated fraction of each assignment.
+func (s *Scheduler) developerUtilization(start, end time.Time) map[string]float64 {
+	busy := make(map[string]map[string]float64)
+	for _, a := range s.Assignments() {
+		if busy[a.Developer] == nil {
+			busy[a.Developer] = make(map[string]float64)
+		}
+		for day := dayOf(a.Start); !day.After(dayOf(a.End)); day = day.AddDate(0, 0, 1) {
+			busy[a.Developer][day.Format("2006-01-02")] += a.Fraction
+		}
+	}
+
+	utilization := make(map[string]float64)
+	for _, dev := range s.developers {
+		working, assigned := 0, 0.0
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			if !s.isEmployed(dev, day) || !s.isWorkingDay(dev, day) {
+				continue
+			}
+			working++
+			assigned += math.Min(busy[dev.Name][day.Format("2006-01-02")], 1)
+		}
+		if working > 0 {
+			utilization[dev.Name] = assigned / float64(working)
+		} else {
+			utilization[dev.Name] = 0
+		}
+	}
+	return utilization
+}
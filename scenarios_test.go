@@ -0,0 +1,201 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newPatchScheduler() *Scheduler {
+	tasks := []*Task{
+		{Name: "A", ParallelFactor: 1, RawEffort: 4, Effort: 6},
+		{Name: "A_QA", Parent: "A", ParallelFactor: 1, RawEffort: 1, Effort: 1.5},
+		{Name: "B", ParallelFactor: 1, RawEffort: 2, Effort: 3,
+			Dependencies: []string{"A", "A_QA"},
+			Links:        []DependencyLink{{Task: "A", Type: StartToStart, Lag: 1}, {Task: "A_QA", Type: FinishToStart}}},
+		{Name: "C", ParallelFactor: 1, RawEffort: 2, Effort: 3},
+	}
+	devs := []*Developer{{Name: "Dev1", Role: "Senior", TaskTypes: []string{"Backend"}}}
+	return NewScheduler(tasks, devs, nil, nil, nil, nil)
+}
+
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   ScenarioPatch
+		wantErr bool
+		check   func(t *testing.T, s *Scheduler)
+	}{
+		{
+			name:  "add developer",
+			patch: ScenarioPatch{Op: PatchAddDeveloper, Developer: "Dev2", Role: "Mid", TaskTypes: []string{"QA"}},
+			check: func(t *testing.T, s *Scheduler) {
+				if s.findDeveloper("Dev2") == nil {
+					t.Error("Dev2 was not added")
+				}
+			},
+		},
+		{
+			name:    "add existing developer",
+			patch:   ScenarioPatch{Op: PatchAddDeveloper, Developer: "Dev1", Role: "Mid", TaskTypes: []string{"QA"}},
+			wantErr: true,
+		},
+		{
+			name:  "developer exit",
+			patch: ScenarioPatch{Op: PatchDeveloperExit, Developer: "Dev1", Date: "2026-11-02"},
+			check: func(t *testing.T, s *Scheduler) {
+				if got := s.findDeveloper("Dev1").ExitDate.Format("2006-01-02"); got != "2026-11-02" {
+					t.Errorf("exit date = %s, want 2026-11-02", got)
+				}
+			},
+		},
+		{
+			name:    "developer exit with bad date",
+			patch:   ScenarioPatch{Op: PatchDeveloperExit, Developer: "Dev1", Date: "next week"},
+			wantErr: true,
+		},
+		{
+			name:  "drop task removes subtasks and links to them",
+			patch: ScenarioPatch{Op: PatchDropTask, Task: "A"},
+			check: func(t *testing.T, s *Scheduler) {
+				if s.findTask("A") != nil || s.findTask("A_QA") != nil {
+					t.Error("A and its subtask should be dropped")
+				}
+				b := s.findTask("B")
+				if len(b.Dependencies) != 0 || len(b.Links) != 0 {
+					t.Errorf("B still depends on %v, links %v", b.Dependencies, b.Links)
+				}
+			},
+		},
+		{
+			name:  "drop task keeps other links",
+			patch: ScenarioPatch{Op: PatchDropTask, Task: "A_QA"},
+			check: func(t *testing.T, s *Scheduler) {
+				b := s.findTask("B")
+				if !reflect.DeepEqual(b.Dependencies, []string{"A"}) {
+					t.Errorf("dependencies = %v, want [A]", b.Dependencies)
+				}
+				if want := []DependencyLink{{Task: "A", Type: StartToStart, Lag: 1}}; !reflect.DeepEqual(b.Links, want) {
+					t.Errorf("links = %v, want %v", b.Links, want)
+				}
+			},
+		},
+		{
+			name:  "set parallel re-inflates effort",
+			patch: ScenarioPatch{Op: PatchSetParallel, Task: "C", Value: 3},
+			check: func(t *testing.T, s *Scheduler) {
+				c := s.findTask("C")
+				if c.ParallelFactor != 3 {
+					t.Errorf("parallel = %d, want 3", c.ParallelFactor)
+				}
+				// 2 days at the default 1.7 factor, rounded to whole hours
+				if c.Effort != 3.375 {
+					t.Errorf("effort = %v, want 3.375", c.Effort)
+				}
+			},
+		},
+		{
+			name:  "set parallel on a parent re-derives its subtasks",
+			patch: ScenarioPatch{Op: PatchSetParallel, Task: "A", Value: 3},
+			check: func(t *testing.T, s *Scheduler) {
+				if a, qa := s.findTask("A"), s.findTask("A_QA"); a.Effort != 6.75 || qa.Effort != 1.75 {
+					t.Errorf("A, A_QA effort = %v, %v, want 6.75, 1.75", a.Effort, qa.Effort)
+				}
+			},
+		},
+		{
+			name:  "set parallel on a subtask keeps its parent's factor",
+			patch: ScenarioPatch{Op: PatchSetParallel, Task: "A_QA", Value: 3},
+			check: func(t *testing.T, s *Scheduler) {
+				if qa := s.findTask("A_QA"); qa.ParallelFactor != 3 || qa.Effort != 1.5 {
+					t.Errorf("A_QA parallel, effort = %d, %v, want 3, 1.5", qa.ParallelFactor, qa.Effort)
+				}
+			},
+		},
+		{
+			name:    "set parallel to a fraction",
+			patch:   ScenarioPatch{Op: PatchSetParallel, Task: "C", Value: 2.5},
+			wantErr: true,
+		},
+		{
+			name:    "set parallel below one",
+			patch:   ScenarioPatch{Op: PatchSetParallel, Task: "C", Value: 0},
+			wantErr: true,
+		},
+		{
+			name:  "set effort",
+			patch: ScenarioPatch{Op: PatchSetEffort, Task: "C", Value: 4},
+			check: func(t *testing.T, s *Scheduler) {
+				c := s.findTask("C")
+				if c.RawEffort != 4 || c.Effort != 6 {
+					t.Errorf("raw effort, effort = %v, %v, want 4, 6", c.RawEffort, c.Effort)
+				}
+			},
+		},
+		{
+			name:  "set effort on a parent re-derives its subtasks",
+			patch: ScenarioPatch{Op: PatchSetEffort, Task: "A", Value: 8},
+			check: func(t *testing.T, s *Scheduler) {
+				qa := s.findTask("A_QA")
+				if qa.RawEffort != 2 || qa.Effort != 3 {
+					t.Errorf("A_QA raw effort, effort = %v, %v, want 2, 3", qa.RawEffort, qa.Effort)
+				}
+			},
+		},
+		{
+			name:    "set effort on unknown task",
+			patch:   ScenarioPatch{Op: PatchSetEffort, Task: "Z", Value: 4},
+			wantErr: true,
+		},
+		{
+			name:    "unknown op",
+			patch:   ScenarioPatch{Op: "rename-task", Task: "C"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newPatchScheduler()
+			err := s.applyPatch(tt.patch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyPatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, s)
+			}
+		})
+	}
+}
+
+func TestCompareScenariosLimit(t *testing.T) {
+	scenarios := make([]Scenario, maxScenarios+1)
+	if _, err := newPatchScheduler().CompareScenarios(monday, scenarios); err == nil {
+		t.Errorf("comparing %d scenarios succeeded, want an error", len(scenarios))
+	}
+}
+
+func TestDeveloperUtilization(t *testing.T) {
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       float64
+	}{
+		{name: "whole days", start: monday, end: monday.AddDate(0, 0, 1), want: 0.4},
+		{name: "ends earlier in the day than it started", start: at(13, 0), end: at(11, 0).AddDate(0, 0, 1), want: 0.4},
+		{name: "within one day", start: at(9, 0), end: at(12, 0), want: 0.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newPatchScheduler()
+			s.roles = map[string]*Role{"Senior": {Name: "Senior", AvailabilityPercent: 1}}
+			s.tasks[0].Assignments = []*Assignment{{Task: "A", Developer: "Dev1", Start: tt.start, End: tt.end, Fraction: 1}}
+			// Monday to Friday, so each day assigned is a fifth of the week
+			got := s.developerUtilization(monday, monday.AddDate(0, 0, 4))
+			if got["Dev1"] != tt.want {
+				t.Errorf("utilization = %v, want %v", got["Dev1"], tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/csv"
 	"fmt"
+	"math"
 	"os"
 	"sort"
 	"strings"
@@ -10,31 +11,134 @@ import (
 )
 
 type Scheduler struct {
-	tasks      []*Task
-	developers []*Developer
-	roles      map[string]*Role
-	oncalls    []OnCall
-	leaves     []Leave
-}
-
-func NewScheduler(tasks []*Task, devs []*Developer, roles map[string]*Role, oncalls []OnCall, leaves []Leave) *Scheduler {
+	tasks        []*Task
+	developers   []*Developer
+	roles        map[string]*Role
+	oncalls      []OnCall
+	leaves       []Leave
+	holidays     []Holiday
+	workWeek     WorkWeek
+	strategy     ScheduleStrategy
+	policy       AssignmentPolicy
+	overhead     OverheadConfig
+	subtaskRules []SubtaskRule
+	progress     []TaskProgress
+	startDate    time.Time
+	criticalPath *CriticalPath
+
+	// bookings tracks how much of each developer's current working day has
+	// been handed out to tasks.
+	bookings map[string]booking
+
+	// contextSwitchPenalty is the fraction of output lost on a task picked
+	// up while the developer is already working on another.
+	contextSwitchPenalty float64
+
+	// preemptive holds the priority levels whose tasks may take developers
+	// off lower-priority work in progress.
+	preemptive map[int]bool
+
+	// splitTasks releases developers from their tasks for full days of
+	// on-call duty leaving no capacity and full-day leave, instead of
+	// stretching the task around it.
+	splitTasks bool
+
+	// taskRank, when set, fixes the order tasks are considered in instead
+	// of sorting by priority.
+	taskRank map[string]int
+
+	// quiet suppresses debug output, used for trial runs.
+	quiet bool
+
+	// deadlineAware schedules tasks at risk of missing their due date
+	// ahead of strict priority order.
+	deadlineAware      bool
+	deadlineViolations []DeadlineViolation
+}
+
+func NewScheduler(tasks []*Task, devs []*Developer, roles map[string]*Role, oncalls []OnCall, leaves []Leave, holidays []Holiday) *Scheduler {
 	return &Scheduler{
 		tasks:      tasks,
 		developers: devs,
 		roles:      roles,
 		oncalls:    oncalls,
 		leaves:     leaves,
+		holidays:   holidays,
+		workWeek:   defaultWorkWeek,
+		strategy:   greedyStrategy{},
+		policy:     priorityPolicy{},
+		overhead:   defaultOverhead,
+
+		subtaskRules: defaultSubtaskRules,
+	}
+}
+
+// clone returns an independent scheduler over copies of the input tasks and
+// developers with all scheduling state cleared, for trial runs.
+func (s *Scheduler) clone() *Scheduler {
+	tasks := make([]*Task, len(s.tasks))
+	for i, task := range s.tasks {
+		tasks[i] = &Task{
+			Name:           task.Name,
+			Priority:       task.Priority,
+			ParallelFactor: task.ParallelFactor,
+			Effort:         task.Effort,
+			RawEffort:      task.RawEffort,
+			Estimate:       task.Estimate,
+			TaskType:       task.TaskType,
+			Team:           task.Team,
+			MinProficiency: task.MinProficiency,
+			AllowedDevs:    task.AllowedDevs,
+			ForbiddenDevs:  task.ForbiddenDevs,
+			EarliestStart:  task.EarliestStart,
+			Parent:         task.Parent,
+			Dependencies:   task.Dependencies,
+			Links:          task.Links,
+			DueDate:        task.DueDate,
+		}
+	}
+
+	developers := make([]*Developer, len(s.developers))
+	for i, dev := range s.developers {
+		copied := *dev
+		copied.NextFreeTime = time.Time{}
+		developers[i] = &copied
+	}
+
+	return &Scheduler{
+		tasks:         tasks,
+		developers:    developers,
+		roles:         s.roles,
+		oncalls:       s.oncalls,
+		leaves:        s.leaves,
+		holidays:      s.holidays,
+		workWeek:      s.workWeek,
+		strategy:      s.strategy,
+		policy:        s.policy,
+		overhead:      s.overhead,
+		progress:      s.progress,
+		taskRank:      s.taskRank,
+		deadlineAware: s.deadlineAware,
+		quiet:         true,
+
+		contextSwitchPenalty: s.contextSwitchPenalty,
+		preemptive:           s.preemptive,
+		splitTasks:           s.splitTasks,
+		subtaskRules:         s.subtaskRules,
 	}
 }
 
 func (s *Scheduler) debug(format string, args ...interface{}) {
+	if s.quiet {
+		return
+	}
 	fmt.Printf("[DEBUG] "+format+"\n", args...)
 }
 
-func (s *Scheduler) areDependenciesCompleted(task *Task) bool {
+func (s *Scheduler) areDependenciesCompleted(task *Task, date time.Time) bool {
 	s.debug("Checking dependencies for task: %s", task.Name)
 	for _, depName := range task.Dependencies {
-		if !s.isDependencyCompleted(depName) {
+		if !s.isDependencyCompleted(task.linkTo(depName), date) {
 			s.debug("Dependency %s not completed for task %s", depName, task.Name)
 			return false
 		}
@@ -43,11 +147,11 @@ func (s *Scheduler) areDependenciesCompleted(task *Task) bool {
 	return true
 }
 
-func (s *Scheduler) isDependencyCompleted(depName string) bool {
+func (s *Scheduler) isDependencyCompleted(link DependencyLink, date time.Time) bool {
 	for _, t := range s.tasks {
-		if t.Name == depName {
-			s.debug("Checking dependency %s: completed=%v", depName, t.IsCompleted)
-			return t.IsCompleted
+		if t.Name == link.Task {
+			s.debug("Checking dependency %s (%s): completed=%v", link.Task, link, t.IsCompleted)
+			return s.canStartAfter(link, t, date)
 		}
 	}
 	return false
@@ -57,6 +161,11 @@ func (s *Scheduler) findAvailableDevs(task *Task, date time.Time) []*Developer {
 	s.debug("Finding available developers for task: %s at date: %v", task.Name, date)
 	var availableDevs []*Developer
 
+	if date.Before(task.EarliestStart) && !sameDay(date, task.EarliestStart) {
+		s.debug("Task %s cannot start before %v", task.Name, task.EarliestStart)
+		return availableDevs
+	}
+
 	for _, dev := range s.developers {
 		if s.isDevAvailableForTask(dev, task, date) {
 			availableDevs = append(availableDevs, dev)
@@ -65,25 +174,55 @@ func (s *Scheduler) findAvailableDevs(task *Task, date time.Time) []*Developer {
 	}
 
 	s.debug("Found %d available developers for task %s", len(availableDevs), task.Name)
+	availableDevs = s.policy.RankDevelopers(s, task, availableDevs, date)
+
+	// Developers returning to a task they were taken off go first
+	sort.SliceStable(availableDevs, func(i, j int) bool {
+		return task.wasInterrupted(availableDevs[i].Name) && !task.wasInterrupted(availableDevs[j].Name)
+	})
 	return availableDevs
 }
 
 func (s *Scheduler) isDevAvailableForTask(dev *Developer, task *Task, date time.Time) bool {
-	if !s.canDevWorkOnTaskType(dev, task.TaskType) {
+	if !s.canDevWorkOnTask(dev, task) {
 		s.debug("Developer %s cannot work on task type: %s", dev.Name, task.TaskType)
 		return false
 	}
 
-	if date.Before(dev.NextFreeTime) {
+	if !s.hasFreeSlot(dev, date) {
 		s.debug("Developer %s is busy until: %v", dev.Name, dev.NextFreeTime)
 		return false
 	}
 
-	if s.isDevOnCall(dev, date) {
+	if s.hoursLeft(dev, date) <= 0 {
+		s.debug("Developer %s has no hours left on %v", dev.Name, date)
 		return false
 	}
 
-	if s.isDevOnLeave(dev, date) {
+	return s.isDevWorking(dev, date)
+}
+
+// isDevWorking reports whether a developer can put any time into work on
+// date, regardless of what they are already doing.
+func (s *Scheduler) isDevWorking(dev *Developer, date time.Time) bool {
+	if s.splitTasks && s.isInterrupted(dev, date) {
+		return false
+	}
+
+	if !s.isEmployed(dev, date) {
+		s.debug("Developer %s is not on the team on %v", dev.Name, date)
+		return false
+	}
+
+	if !s.isWorkingDay(dev, date) {
+		return false
+	}
+
+	if s.onCallCapacity(dev, date) <= 0 {
+		return false
+	}
+
+	if s.leaveCapacity(dev, date) <= 0 {
 		return false
 	}
 
@@ -99,31 +238,84 @@ func (s *Scheduler) canDevWorkOnTaskType(dev *Developer, taskType string) bool {
 	return false
 }
 
-func (s *Scheduler) isDevOnCall(dev *Developer, date time.Time) bool {
-	for _, oncall := range s.oncalls {
-		if oncall.DevName == dev.Name &&
-			!date.Before(oncall.StartTime) &&
-			!date.After(oncall.EndTime) {
+// canDevWorkOnTask checks the task type, the task's minimum proficiency and
+// its allowed and forbidden developers.
+func (s *Scheduler) canDevWorkOnTask(dev *Developer, task *Task) bool {
+	if !s.canDevWorkOnTaskType(dev, task.TaskType) {
+		return false
+	}
+	if len(task.AllowedDevs) > 0 && !containsString(task.AllowedDevs, dev.Name) {
+		return false
+	}
+	if containsString(task.ForbiddenDevs, dev.Name) {
+		return false
+	}
+	return s.proficiency(dev, task.TaskType) >= task.MinProficiency
+}
+
+// proficiency returns how effective a developer is at a task type, 1 when
+// listed without a level and 0 when not listed at all.
+func (s *Scheduler) proficiency(dev *Developer, taskType string) float64 {
+	if !s.canDevWorkOnTaskType(dev, taskType) {
+		return 0
+	}
+	if level, ok := dev.Proficiency[taskType]; ok {
+		return level
+	}
+	return 1
+}
+
+func (s *Scheduler) findOnCall(dev *Developer, date time.Time) *OnCall {
+	for i, oncall := range s.oncalls {
+		if oncall.DevName == dev.Name && s.coverage(dev, date, oncall.StartTime, oncall.EndTime) > 0 {
 			s.debug("Developer %s is on-call between %v and %v", dev.Name, oncall.StartTime, oncall.EndTime)
-			return true
+			return &s.oncalls[i]
 		}
 	}
-	return false
+	return nil
+}
+
+// onCallCapacity returns the fraction of normal output a developer delivers
+// on date: 1 when not on call, otherwise the on-call row's capacity or the
+// role default for the part of the day the on-call duty covers.
+func (s *Scheduler) onCallCapacity(dev *Developer, date time.Time) float64 {
+	oncall := s.findOnCall(dev, date)
+	if oncall == nil {
+		return 1
+	}
+	covered := s.coverage(dev, date, oncall.StartTime, oncall.EndTime)
+	return 1 - covered*(1-s.onCallRowCapacity(dev, *oncall))
+}
+
+// onCallRowCapacity returns the output a developer keeps during an on-call
+// row: its own capacity, or the role default.
+func (s *Scheduler) onCallRowCapacity(dev *Developer, oncall OnCall) float64 {
+	if oncall.Capacity != nil {
+		return *oncall.Capacity
+	}
+	if role, exists := s.roles[dev.Role]; exists {
+		return role.OnCallCapacity
+	}
+	return 0
 }
 
-func (s *Scheduler) isDevOnLeave(dev *Developer, date time.Time) bool {
+// leaveCapacity returns the fraction of the day a developer is not on leave.
+// Overlapping leaves do not stack; the largest fraction wins.
+func (s *Scheduler) leaveCapacity(dev *Developer, date time.Time) float64 {
+	onLeave := 0.0
 	for _, leave := range s.leaves {
-		if leave.DevName == dev.Name &&
-			!date.Before(leave.StartTime) &&
-			!date.After(leave.EndTime) {
-			s.debug("Developer %s is on leave between %v and %v", dev.Name, leave.StartTime, leave.EndTime)
-			return true
+		if leave.DevName != dev.Name {
+			continue
+		}
+		if covered := s.coverage(dev, date, leave.StartTime, leave.EndTime); covered > 0 {
+			s.debug("Developer %s is on leave (%.2f) between %v and %v", dev.Name, leave.Fraction, leave.StartTime, leave.EndTime)
+			onLeave = math.Max(onLeave, covered*leave.Fraction)
 		}
 	}
-	return false
+	return 1 - onLeave
 }
 
-func (s *Scheduler) calculateEndDate(devs []*Developer, startDate time.Time, effortPerDev float64) time.Time {
+func (s *Scheduler) calculateEndDate(task *Task, devs []*Developer, startDate time.Time, effortPerDev float64) time.Time {
 	s.debug("Calculating end date for effort %.2f starting at %v", effortPerDev, startDate)
 	currentDate := startDate
 	remainingEffort := effortPerDev
@@ -131,19 +323,19 @@ func (s *Scheduler) calculateEndDate(devs []*Developer, startDate time.Time, eff
 
 	iterations := 0
 	for remainingEffort > 0 && iterations < maxIterations {
-		if isWeekend(currentDate) {
+		if !s.isAnyWorkingDay(devs, currentDate) {
 			currentDate = currentDate.AddDate(0, 0, 1)
 			continue
 		}
 
 		availableDevs := make([]*Developer, 0)
 		for _, dev := range devs {
-			if !s.isDevOnCall(dev, currentDate) && !s.isDevOnLeave(dev, currentDate) {
+			if s.isWorkingDay(dev, currentDate) {
 				availableDevs = append(availableDevs, dev)
 			}
 		}
 
-		dailyProgress := s.calculateDailyProgress(availableDevs)
+		dailyProgress := s.calculateDailyProgress(task, availableDevs, currentDate)
 		if dailyProgress > 0 {
 			remainingEffort -= dailyProgress
 		}
@@ -163,21 +355,74 @@ func (s *Scheduler) calculateEndDate(devs []*Developer, startDate time.Time, eff
 	return currentDate
 }
 
-func (s *Scheduler) calculateDailyProgress(devs []*Developer) float64 {
+func (s *Scheduler) calculateDailyProgress(task *Task, devs []*Developer, date time.Time) float64 {
 	dailyProgress := 0.0
 	for _, dev := range devs {
-		if role, exists := s.roles[dev.Role]; exists {
-			availability := role.AvailabilityPercent
-			dailyProgress += availability
-			s.debug("Developer %s contributes %.2f progress with %.2f availability",
-				dev.Name, availability, role.AvailabilityPercent)
-		}
+		baseAvailability := s.availability(dev, date)
+		availability := s.projectHours(dev, date) * s.dailyShare(dev, date) * s.hourlyOutput(dev, task, date)
+		dailyProgress += availability
+		s.debug("Developer %s contributes %.2f progress with %.2f availability",
+			dev.Name, availability, baseAvailability)
 	}
 	return dailyProgress
 }
 
+// isEmployed reports whether date falls between the developer's join and
+// exit dates, both inclusive.
+func (s *Scheduler) isEmployed(dev *Developer, date time.Time) bool {
+	if !dev.JoinDate.IsZero() && date.Before(dev.JoinDate) && !sameDay(date, dev.JoinDate) {
+		return false
+	}
+	if !dev.ExitDate.IsZero() && date.After(dev.ExitDate) && !sameDay(date, dev.ExitDate) {
+		return false
+	}
+	return true
+}
+
+// rampUpFactor returns the share of full output a developer delivers on date,
+// following their ramp-up steps from the join date. Outside employment it is 0.
+func (s *Scheduler) rampUpFactor(dev *Developer, date time.Time) float64 {
+	if !s.isEmployed(dev, date) {
+		return 0
+	}
+	if dev.JoinDate.IsZero() {
+		return 1
+	}
+	stepEnd := dev.JoinDate
+	for _, step := range dev.RampUp {
+		stepEnd = stepEnd.AddDate(0, 0, step.Days)
+		if date.Before(stepEnd) {
+			return step.Fraction
+		}
+	}
+	return 1
+}
+
+// availability resolves a developer's availability on date: the latest of
+// their own availability periods that has started, otherwise their role's.
+func (s *Scheduler) availability(dev *Developer, date time.Time) float64 {
+	for i := len(dev.Availability) - 1; i >= 0; i-- {
+		if !date.Before(dev.Availability[i].From) {
+			return dev.Availability[i].Percent
+		}
+	}
+	if role, exists := s.roles[dev.Role]; exists {
+		return role.AvailabilityPercent
+	}
+	return 0
+}
+
 func (s *Scheduler) Schedule(startDate time.Time) {
 	s.debug("Starting scheduling from date: %v", startDate)
+	s.strategy.Schedule(s, startDate)
+
+	s.criticalPath = s.analyzeCriticalPath()
+	s.deadlineViolations = s.findDeadlineViolations()
+	s.writeScheduleToCSV()
+}
+
+// simulate runs the day-by-day assignment loop from startDate.
+func (s *Scheduler) simulate(startDate time.Time) {
 	s.initializeSchedule(startDate)
 
 	currentDate := startDate
@@ -188,21 +433,31 @@ func (s *Scheduler) Schedule(startDate time.Time) {
 		if s.processSchedulingIteration(currentDate) {
 			break
 		}
-		currentDate = addWorkdays(currentDate, 1)
+		currentDate = s.addWorkdays(currentDate, 1)
 		iterations++
 	}
 
 	if iterations >= maxIterations {
 		s.debug("WARNING: Max scheduling iterations reached")
 	}
-
-	s.writeScheduleToCSV()
 }
 
 func (s *Scheduler) initializeSchedule(startDate time.Time) {
 	s.tasks = s.filterTasksWithValidDevs()
-	s.sortTasksByPriority()
+	// A fixed rank wins over deadline-aware ordering, which wins over the
+	// policy's own order
+	switch {
+	case s.taskRank != nil:
+		s.sortTasksByRank()
+	case s.deadlineAware:
+		s.sortTasksByDeadlineRisk(startDate)
+	default:
+		s.policy.OrderTasks(s, startDate)
+	}
 	s.initializeDevStartTimes(startDate)
+	s.startDate = startDate
+	s.bookings = make(map[string]booking)
+	s.applyProgress(startDate)
 }
 
 func (s *Scheduler) filterTasksWithValidDevs() []*Task {
@@ -217,10 +472,8 @@ func (s *Scheduler) filterTasksWithValidDevs() []*Task {
 
 func (s *Scheduler) hasMatchingDeveloper(task *Task) bool {
 	for _, dev := range s.developers {
-		for _, devTaskType := range dev.TaskTypes {
-			if devTaskType == task.TaskType {
-				return true
-			}
+		if s.canDevWorkOnTask(dev, task) {
+			return true
 		}
 	}
 	return false
@@ -232,6 +485,12 @@ func (s *Scheduler) sortTasksByPriority() {
 	})
 }
 
+func (s *Scheduler) sortTasksByRank() {
+	sort.SliceStable(s.tasks, func(i, j int) bool {
+		return s.taskRank[s.tasks[i].Name] < s.taskRank[s.tasks[j].Name]
+	})
+}
+
 func (s *Scheduler) initializeDevStartTimes(startDate time.Time) {
 	for _, dev := range s.developers {
 		dev.NextFreeTime = startDate
@@ -239,17 +498,41 @@ func (s *Scheduler) initializeDevStartTimes(startDate time.Time) {
 }
 
 func (s *Scheduler) processSchedulingIteration(currentDate time.Time) bool {
-	completedAll := true
-
 	s.processCompletedTasks()
 
 	for _, task := range s.tasks {
-		if !s.processTask(task, currentDate) {
-			completedAll = false
+		if !task.IsCompleted && len(task.AssignedDevs) > 0 {
+			s.releaseAbsentDevs(task, currentDate)
+		}
+	}
+
+	// Developers are handed their tasks before any work is recorded, so a
+	// developer on several tasks splits the day between them. Another round
+	// follows whenever developers picked up work or freed up during the day.
+	for {
+		staffed := false
+		for _, task := range s.tasks {
+			if s.staffTask(task, currentDate) {
+				staffed = true
+			}
+		}
+		finished := false
+		for _, task := range s.tasks {
+			if s.workOnTask(task, currentDate) {
+				finished = true
+			}
+		}
+		if !staffed && !finished {
+			break
 		}
 	}
 
-	return completedAll
+	for _, task := range s.tasks {
+		if !task.IsCompleted {
+			return false
+		}
+	}
+	return true
 }
 
 func (s *Scheduler) processCompletedTasks() {
@@ -260,21 +543,37 @@ func (s *Scheduler) processCompletedTasks() {
 	}
 }
 
-func (s *Scheduler) processTask(task *Task, currentDate time.Time) bool {
+// staffTask assigns the developers free to work on a task on currentDate and
+// reports whether it gained any.
+func (s *Scheduler) staffTask(task *Task, currentDate time.Time) bool {
 	if task.IsCompleted {
-		return true
+		return false
 	}
 
-	if !s.areDependenciesCompleted(task) {
+	// Work already under way is not held back by its dependencies
+	if !task.InProgress && !s.areDependenciesCompleted(task, currentDate) {
 		return false
 	}
 
+	staffed := len(task.AssignedDevs)
 	availableDevs := s.findAvailableDevs(task, currentDate)
 	if len(availableDevs) > 0 {
 		s.assignDevsToTask(task, availableDevs, currentDate)
-		s.updateTaskCompletion(task, currentDate)
 	}
+	if s.preemptive[task.Priority] && len(task.AssignedDevs) < task.ParallelFactor {
+		s.preemptFor(task, currentDate)
+	}
+	return len(task.AssignedDevs) > staffed
+}
 
+// workOnTask records the day's work on a task and reports whether the task
+// finished.
+func (s *Scheduler) workOnTask(task *Task, currentDate time.Time) bool {
+	if task.IsCompleted || len(task.AssignedDevs) == 0 {
+		return false
+	}
+	s.recordProgress(task, currentDate)
+	s.updateTaskCompletion(task, currentDate)
 	return task.IsCompleted
 }
 
@@ -283,6 +582,18 @@ func (s *Scheduler) assignDevsToTask(task *Task, availableDevs []*Developer, cur
 		s.initializeTaskAssignment(task, currentDate)
 	}
 
+	// Only take developers the task allows who are not already on it
+	var allowedDevs []*Developer
+	for _, dev := range availableDevs {
+		if task.isAssigned(dev.Name) {
+			continue
+		}
+		if s.canDevWorkOnTask(dev, task) {
+			allowedDevs = append(allowedDevs, dev)
+		}
+	}
+	availableDevs = allowedDevs
+
 	remainingSlots := task.ParallelFactor - len(task.AssignedDevs)
 	if remainingSlots > 0 {
 		s.fillTaskSlots(task, availableDevs, remainingSlots, currentDate)
@@ -291,12 +602,12 @@ func (s *Scheduler) assignDevsToTask(task *Task, availableDevs []*Developer, cur
 
 func (s *Scheduler) initializeTaskAssignment(task *Task, currentDate time.Time) {
 	task.AssignedDevs = make([]*Developer, 0)
-	task.DevStartTimes = make(map[string]time.Time)
 	task.StartTime = currentDate
 }
 
 func (s *Scheduler) fillTaskSlots(task *Task, availableDevs []*Developer, remainingSlots int, currentDate time.Time) {
-	slotsToFill := min(len(availableDevs), remainingSlots)
+	slotsToFill := s.policy.SlotsToFill(s, task, availableDevs, remainingSlots)
+	slotsToFill = max(0, min(slotsToFill, min(len(availableDevs), remainingSlots)))
 	newDevs := availableDevs[:slotsToFill]
 
 	if len(newDevs) == 0 {
@@ -304,35 +615,38 @@ func (s *Scheduler) fillTaskSlots(task *Task, availableDevs []*Developer, remain
 		return
 	}
 
-	task.AssignedDevs = append(task.AssignedDevs, newDevs...)
-
 	for _, dev := range newDevs {
-		task.DevStartTimes[dev.Name] = currentDate
+		task.startAssignment(dev.Name, currentDate, s.assignmentShare(dev, currentDate), 0)
 	}
+	task.AssignedDevs = append(task.AssignedDevs, newDevs...)
 
-	s.updateTaskEndTime(task, newDevs, currentDate)
+	s.updateTaskEndTime(task, currentDate)
 }
 
-func (s *Scheduler) updateTaskEndTime(task *Task, newDevs []*Developer, currentDate time.Time) {
-	daysWorked := currentDate.Sub(task.StartTime).Hours() / 24
-	progressPerDay := 0.0
-	for _, dev := range task.AssignedDevs[:len(task.AssignedDevs)-len(newDevs)] {
-		if role, exists := s.roles[dev.Role]; exists {
-			progressPerDay += role.AvailabilityPercent
-		}
-	}
-	remainingEffort := task.Effort - (progressPerDay * daysWorked)
-	task.EndTime = s.calculateEndDate(task.AssignedDevs, currentDate, remainingEffort)
+// updateTaskEndTime projects when the task's current developers will finish
+// the effort left in its ledger.
+func (s *Scheduler) updateTaskEndTime(task *Task, currentDate time.Time) {
+	task.EndTime = s.calculateEndDate(task, task.AssignedDevs, currentDate, task.remainingEffort())
 
 	for _, dev := range task.AssignedDevs {
-		dev.NextFreeTime = task.EndTime
+		s.refreshNextFreeTime(dev)
 	}
 }
 
 func (s *Scheduler) updateTaskCompletion(task *Task, currentDate time.Time) {
-	if !currentDate.Before(task.EndTime) {
-		task.IsCompleted = true
+	if task.remainingEffort() > effortEpsilon || !s.canFinishAfter(task, currentDate) {
+		return
+	}
+	// The ledger, not the projection, says when the work actually finished
+	task.EndTime = task.finishedAt()
+	if at := s.finishNotBefore(task); at.After(task.EndTime) {
+		task.EndTime = at
 	}
+	if task.EndTime.IsZero() {
+		task.EndTime = currentDate
+	}
+	task.IsCompleted = true
+	task.endAssignments()
 }
 
 func (s *Scheduler) writeScheduleToCSV() {
@@ -351,7 +665,7 @@ func (s *Scheduler) writeScheduleToCSV() {
 }
 
 func (s *Scheduler) writeCSVHeader(writer *csv.Writer) {
-	header := []string{"Task", "Start Date", "End Date", "Assigned Developers", "Effort Per Developer"}
+	header := []string{"Task", "Start Date", "End Date", "Assigned Developers", "Effort Per Developer", "Allocation", "Effort Delivered"}
 	if err := writer.Write(header); err != nil {
 		s.debug("Error writing header: %v", err)
 	}
@@ -379,10 +693,12 @@ func (s *Scheduler) writeOncallRecords(writeRecord func([]string)) {
 	for _, oncall := range s.oncalls {
 		record := []string{
 			"On-Call Duty",
-			oncall.StartTime.Format("2006-01-02"),
-			oncall.EndTime.Format("2006-01-02"),
+			oncall.StartTime.Format(timestampLayout),
+			oncall.EndTime.Format(timestampLayout),
 			oncall.DevName,
 			fmt.Sprintf("%.2f", float64(oncall.EndTime.Sub(oncall.StartTime).Hours()/24)),
+			"",
+			"",
 		}
 		writeRecord(record)
 	}
@@ -392,30 +708,32 @@ func (s *Scheduler) writeLeaveRecords(writeRecord func([]string)) {
 	for _, leave := range s.leaves {
 		record := []string{
 			"Leave",
-			leave.StartTime.Format("2006-01-02"),
-			leave.EndTime.Format("2006-01-02"),
+			leave.StartTime.Format(timestampLayout),
+			leave.EndTime.Format(timestampLayout),
 			leave.DevName,
 			fmt.Sprintf("%.2f", float64(leave.EndTime.Sub(leave.StartTime).Hours()/24)),
+			"",
+			"",
 		}
 		writeRecord(record)
 	}
 }
 
 func (s *Scheduler) writeTaskRecords(writeRecord func([]string)) {
-	for _, task := range s.tasks {
-		if !task.IsCompleted {
+	for _, a := range s.Assignments() {
+		if task := s.findTask(a.Task); task == nil || !task.IsCompleted {
 			continue
 		}
-		for devName, startTime := range task.DevStartTimes {
-			record := []string{
-				task.Name,
-				startTime.Format("2006-01-02"),
-				task.EndTime.Format("2006-01-02"),
-				strings.TrimSpace(devName),
-				fmt.Sprintf("%.2f", float64(task.EndTime.Sub(startTime).Hours()/24)),
-			}
-			writeRecord(record)
+		record := []string{
+			a.Task,
+			a.Start.Format(timestampLayout),
+			a.End.Format(timestampLayout),
+			strings.TrimSpace(a.Developer),
+			fmt.Sprintf("%.2f", float64(a.End.Sub(a.Start).Hours()/24)),
+			fmt.Sprintf("%.2f", a.Fraction),
+			fmt.Sprintf("%.2f", a.Effort),
 		}
+		writeRecord(record)
 	}
 }
 
@@ -431,20 +749,13 @@ func (s *Scheduler) processCompletedTask(task *Task) {
 	}
 }
 
-func isWeekend(date time.Time) bool {
-	day := date.Weekday()
-	return day == time.Saturday || day == time.Sunday
-}
-
-func addWorkdays(date time.Time, days int) time.Time {
-	result := date
-	for days > 0 {
-		result = result.AddDate(0, 0, 1)
-		if !isWeekend(result) {
-			days--
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
 		}
 	}
-	return result
+	return false
 }
 
 func min(a, b int) int {
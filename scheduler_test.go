@@ -0,0 +1,255 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCanDevWorkOnTask(t *testing.T) {
+	dev := &Developer{Name: "Dev1", TaskTypes: []string{"Backend", "Frontend"}, Proficiency: map[string]float64{"Frontend": 0.5}}
+	tests := []struct {
+		name string
+		task *Task
+		want bool
+	}{
+		{name: "matching task type", task: &Task{TaskType: "Backend"}, want: true},
+		{name: "other task type", task: &Task{TaskType: "QA"}},
+		{name: "allowed", task: &Task{TaskType: "Backend", AllowedDevs: []string{"Dev2", "Dev1"}}, want: true},
+		{name: "not among the allowed", task: &Task{TaskType: "Backend", AllowedDevs: []string{"Dev2"}}},
+		{name: "forbidden", task: &Task{TaskType: "Backend", ForbiddenDevs: []string{"Dev1"}}},
+		{name: "someone else forbidden", task: &Task{TaskType: "Backend", ForbiddenDevs: []string{"Dev2"}}, want: true},
+		{name: "allowed but forbidden", task: &Task{TaskType: "Backend", AllowedDevs: []string{"Dev1"}, ForbiddenDevs: []string{"Dev1"}}},
+		{name: "proficient enough", task: &Task{TaskType: "Frontend", MinProficiency: 0.5}, want: true},
+		{name: "not proficient enough", task: &Task{TaskType: "Frontend", MinProficiency: 0.6}},
+		{name: "type without a level counts as fully proficient", task: &Task{TaskType: "Backend", MinProficiency: 1}, want: true},
+	}
+
+	s := NewScheduler(nil, []*Developer{dev}, nil, nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.canDevWorkOnTask(dev, tt.task); got != tt.want {
+				t.Errorf("canDevWorkOnTask = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTaskConstraints(t *testing.T) {
+	devs := []*Developer{{Name: "Dev1"}, {Name: "Dev2"}}
+	tests := []struct {
+		name    string
+		task    *Task
+		wantErr bool
+	}{
+		{name: "no constraints", task: &Task{Name: "A"}},
+		{name: "known developers", task: &Task{Name: "A", AllowedDevs: []string{"Dev1"}, ForbiddenDevs: []string{"Dev2"}}},
+		{name: "unknown allowed developer", task: &Task{Name: "A", AllowedDevs: []string{"Dev1", "Dev9"}}, wantErr: true},
+		{name: "unknown forbidden developer", task: &Task{Name: "A", ForbiddenDevs: []string{"dev1"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateTaskConstraints([]*Task{tt.task}, devs); (err != nil) != tt.wantErr {
+				t.Errorf("validateTaskConstraints error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEarliestStart(t *testing.T) {
+	wednesday := monday.AddDate(0, 0, 2)
+	tests := []struct {
+		name     string
+		earliest time.Time
+		date     time.Time
+		want     int
+	}{
+		{name: "before the earliest start", earliest: wednesday, date: monday.AddDate(0, 0, 1)},
+		{name: "on the earliest start", earliest: wednesday, date: wednesday, want: 1},
+		{name: "on the day of a mid-day earliest start", earliest: wednesday.Add(13 * time.Hour), date: wednesday, want: 1},
+		{name: "after the earliest start", earliest: wednesday, date: monday.AddDate(0, 0, 3), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newWIPScheduler(1, 0, 1)
+			task := s.tasks[0]
+			task.EarliestStart = tt.earliest
+			if got := len(s.findAvailableDevs(task, tt.date)); got != tt.want {
+				t.Errorf("%d developers available, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEarliestStartHoldsBackPreemption(t *testing.T) {
+	wednesday := monday.AddDate(0, 0, 2)
+	refactor := &Task{Name: "Refactor", TaskType: "Backend", Priority: 3, ParallelFactor: 1, Effort: 3}
+	hotfix := &Task{Name: "Hotfix", TaskType: "Backend", Priority: 1, ParallelFactor: 1, Effort: 1, EarliestStart: wednesday.Add(13 * time.Hour)}
+	devs := []*Developer{{Name: "Dev1", Role: "Senior", TaskTypes: []string{"Backend"}}}
+	roles := map[string]*Role{"Senior": {Name: "Senior", AvailabilityPercent: 1}}
+	s := NewScheduler([]*Task{refactor, hotfix}, devs, roles, nil, nil, nil)
+	s.quiet = true
+	s.preemptive = map[int]bool{1: true}
+	s.simulate(monday)
+
+	// Dev1 stays on the refactor until the hotfix may start at 13:00 on
+	// Wednesday, then returns to it once the hotfix is done
+	refactorWant := []string{"10-12 09:00-17:00", "10-13 09:00-17:00", "10-14 09:00-13:00", "10-15 13:00-17:00"}
+	if got := ledgerHours(refactor, monday, wednesday.AddDate(0, 0, 7)); !reflect.DeepEqual(got, refactorWant) {
+		t.Errorf("refactor work = %v, want %v", got, refactorWant)
+	}
+	hotfixWant := []string{"10-14 13:00-17:00", "10-15 09:00-13:00"}
+	if got := ledgerHours(hotfix, monday, wednesday.AddDate(0, 0, 7)); !reflect.DeepEqual(got, hotfixWant) {
+		t.Errorf("hotfix work = %v, want %v", got, hotfixWant)
+	}
+}
+
+func TestOnCallCapacity(t *testing.T) {
+	half := 0.5
+	none := 0.0
+	roles := map[string]*Role{"Senior": {Name: "Senior", AvailabilityPercent: 1, OnCallCapacity: 0.25}}
+	dev := &Developer{Name: "Dev1", Role: "Senior"}
+	tests := []struct {
+		name   string
+		oncall OnCall
+		want   float64
+	}{
+		{name: "not on call", oncall: OnCall{DevName: "Dev2", StartTime: monday, EndTime: monday}, want: 1},
+		{name: "another day", oncall: OnCall{DevName: "Dev1", StartTime: monday.AddDate(0, 0, 1), EndTime: monday.AddDate(0, 0, 2)}, want: 1},
+		{name: "role default", oncall: OnCall{DevName: "Dev1", StartTime: monday, EndTime: monday}, want: 0.25},
+		{name: "own capacity", oncall: OnCall{DevName: "Dev1", StartTime: monday, EndTime: monday, Capacity: &half}, want: 0.5},
+		{name: "no capacity", oncall: OnCall{DevName: "Dev1", StartTime: monday, EndTime: monday, Capacity: &none}, want: 0},
+		{name: "half a day without capacity", oncall: OnCall{DevName: "Dev1", StartTime: at(9, 0), EndTime: at(13, 0), Capacity: &none}, want: 0.5},
+		{name: "half a day at half capacity", oncall: OnCall{DevName: "Dev1", StartTime: at(13, 0), EndTime: at(17, 0), Capacity: &half}, want: 0.75},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScheduler(nil, []*Developer{dev}, roles, []OnCall{tt.oncall}, nil, nil)
+			s.quiet = true
+			if got := s.onCallCapacity(dev, monday); got != tt.want {
+				t.Errorf("onCallCapacity = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeaveCapacity(t *testing.T) {
+	dev := &Developer{Name: "Dev1"}
+	tests := []struct {
+		name   string
+		leaves []Leave
+		want   float64
+	}{
+		{name: "no leave", want: 1},
+		{name: "someone else's leave", leaves: []Leave{{DevName: "Dev2", StartTime: monday, EndTime: monday, Fraction: 1}}, want: 1},
+		{name: "full day", leaves: []Leave{{DevName: "Dev1", StartTime: monday, EndTime: monday, Fraction: 1}}, want: 0},
+		{name: "half of every day", leaves: []Leave{{DevName: "Dev1", StartTime: monday.AddDate(0, 0, -7), EndTime: monday.AddDate(0, 0, 7), Fraction: 0.5}}, want: 0.5},
+		{name: "morning off", leaves: []Leave{{DevName: "Dev1", StartTime: at(9, 0), EndTime: at(11, 0), Fraction: 1}}, want: 0.75},
+		{name: "fraction of the hours", leaves: []Leave{{DevName: "Dev1", StartTime: at(9, 0), EndTime: at(13, 0), Fraction: 0.5}}, want: 0.75},
+		{
+			name: "overlapping leaves do not stack",
+			leaves: []Leave{
+				{DevName: "Dev1", StartTime: monday, EndTime: monday, Fraction: 0.2},
+				{DevName: "Dev1", StartTime: monday, EndTime: monday.AddDate(0, 0, 1), Fraction: 0.6},
+				{DevName: "Dev1", StartTime: at(9, 0), EndTime: at(13, 0), Fraction: 1},
+			},
+			want: 0.4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScheduler(nil, []*Developer{dev}, nil, nil, tt.leaves, nil)
+			s.quiet = true
+			if got := s.leaveCapacity(dev, monday); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("leaveCapacity = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAvailability(t *testing.T) {
+	roles := map[string]*Role{"Senior": {Name: "Senior", AvailabilityPercent: 0.8}}
+	periods := []AvailabilityPeriod{
+		{Percent: 0.5},
+		{From: monday, Percent: 1},
+		{From: monday.AddDate(0, 0, 7), Percent: 0.2},
+	}
+	tests := []struct {
+		name string
+		dev  *Developer
+		date time.Time
+		want float64
+	}{
+		{name: "role default", dev: &Developer{Role: "Senior"}, date: monday, want: 0.8},
+		{name: "unknown role", dev: &Developer{Role: "Intern"}, date: monday, want: 0},
+		{name: "open period before the first dated one", dev: &Developer{Role: "Senior", Availability: periods}, date: monday.AddDate(0, 0, -1), want: 0.5},
+		{name: "period starting that day", dev: &Developer{Role: "Senior", Availability: periods}, date: monday, want: 1},
+		{name: "latest started period", dev: &Developer{Role: "Senior", Availability: periods}, date: monday.AddDate(0, 1, 0), want: 0.2},
+		{name: "role before the first period", dev: &Developer{Role: "Senior", Availability: periods[1:]}, date: monday.AddDate(0, 0, -1), want: 0.8},
+	}
+
+	s := NewScheduler(nil, nil, roles, nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.availability(tt.dev, tt.date); got != tt.want {
+				t.Errorf("availability = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRampUpFactor(t *testing.T) {
+	rampUp := []RampUpStep{{Fraction: 0.25, Days: 7}, {Fraction: 0.5, Days: 14}}
+	tests := []struct {
+		name string
+		dev  *Developer
+		date time.Time
+		want float64
+	}{
+		{name: "no join date", dev: &Developer{RampUp: rampUp}, date: monday, want: 1},
+		{name: "before joining", dev: &Developer{JoinDate: monday, RampUp: rampUp}, date: monday.AddDate(0, 0, -1), want: 0},
+		{name: "join day", dev: &Developer{JoinDate: monday, RampUp: rampUp}, date: monday, want: 0.25},
+		{name: "last day of the first step", dev: &Developer{JoinDate: monday, RampUp: rampUp}, date: monday.AddDate(0, 0, 6), want: 0.25},
+		{name: "second step", dev: &Developer{JoinDate: monday, RampUp: rampUp}, date: monday.AddDate(0, 0, 7), want: 0.5},
+		{name: "ramped up", dev: &Developer{JoinDate: monday, RampUp: rampUp}, date: monday.AddDate(0, 0, 21), want: 1},
+		{name: "joined without ramp-up", dev: &Developer{JoinDate: monday}, date: monday, want: 1},
+		{name: "exit day", dev: &Developer{ExitDate: monday}, date: monday, want: 1},
+		{name: "after leaving", dev: &Developer{ExitDate: monday}, date: monday.AddDate(0, 0, 1), want: 0},
+		{name: "leaving while ramping up", dev: &Developer{JoinDate: monday, ExitDate: monday.AddDate(0, 0, 3), RampUp: rampUp}, date: monday.AddDate(0, 0, 4), want: 0},
+	}
+
+	s := NewScheduler(nil, nil, nil, nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.rampUpFactor(tt.dev, tt.date); got != tt.want {
+				t.Errorf("rampUpFactor = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProficiencySlowsProgress(t *testing.T) {
+	tests := []struct {
+		proficiency map[string]float64
+		want        time.Time
+	}{
+		{want: time.Date(2026, 10, 13, 17, 0, 0, 0, time.UTC)},
+		{proficiency: map[string]float64{"Backend": 1}, want: time.Date(2026, 10, 13, 17, 0, 0, 0, time.UTC)},
+		{proficiency: map[string]float64{"Backend": 0.5}, want: time.Date(2026, 10, 15, 17, 0, 0, 0, time.UTC)},
+		{proficiency: map[string]float64{"Frontend": 0.5}, want: time.Date(2026, 10, 13, 17, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		s := newWIPScheduler(1, 0, 2)
+		s.developers[0].Proficiency = tt.proficiency
+		s.simulate(monday)
+		if got := s.tasks[0].EndTime; !got.Equal(tt.want) {
+			t.Errorf("proficiency %v: end = %v, want %v", tt.proficiency, got, tt.want)
+		}
+	}
+}
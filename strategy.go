@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ScheduleStrategy decides how a scheduler turns its inputs into a schedule.
+type ScheduleStrategy interface {
+	Schedule(s *Scheduler, startDate time.Time)
+}
+
+func newScheduleStrategy(name string) (ScheduleStrategy, error) {
+	switch name {
+	case "", "greedy":
+		return greedyStrategy{}, nil
+	case "optimize":
+		return annealingStrategy{
+			iterations:  annealingIterations,
+			temperature: annealingTemperature,
+			seed:        1,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown scheduling strategy %q", name)
+	}
+}
+
+// greedyStrategy walks tasks in priority order day by day and assigns
+// whoever is free.
+type greedyStrategy struct{}
+
+func (greedyStrategy) Schedule(s *Scheduler, startDate time.Time) {
+	s.simulate(startDate)
+}
+
+const (
+	annealingIterations  = 300
+	annealingTemperature = 5.0 // In working days of makespan
+
+	// priorityPenaltyWeight scales the soft priority term against makespan.
+	priorityPenaltyWeight = 0.2
+
+	// unfinishedTaskPenalty is charged for every task a trial leaves
+	// unscheduled so such trials are never preferred.
+	unfinishedTaskPenalty = 1000.0
+)
+
+// annealingStrategy searches over the order tasks are considered in and the
+// order developers are preferred in, using simulated annealing with the
+// greedy simulation as the evaluation function. It minimises the overall
+// finish date, with priorities acting as a soft constraint.
+type annealingStrategy struct {
+	iterations  int
+	temperature float64
+	seed        int64
+}
+
+func (a annealingStrategy) Schedule(s *Scheduler, startDate time.Time) {
+	rng := rand.New(rand.NewSource(a.seed))
+
+	// Start from the greedy order
+	base := s.clone()
+	base.initializeSchedule(startDate)
+	taskOrder := make([]string, len(base.tasks))
+	for i, task := range base.tasks {
+		taskOrder[i] = task.Name
+	}
+	devOrder := make([]string, len(s.developers))
+	for i, dev := range s.developers {
+		devOrder[i] = dev.Name
+	}
+
+	evaluate := func(taskOrder, devOrder []string) float64 {
+		trial := s.clone()
+		trial.taskRank = rankOf(taskOrder)
+		trial.developers = orderDevelopers(trial.developers, devOrder)
+		trial.simulate(startDate)
+		return trial.scheduleCost(startDate)
+	}
+
+	current := evaluate(taskOrder, devOrder)
+	bestCost := current
+	bestTasks := append([]string(nil), taskOrder...)
+	bestDevs := append([]string(nil), devOrder...)
+	s.debug("Optimizer starting cost: %.2f", current)
+
+	for i := 0; i < a.iterations; i++ {
+		temperature := a.temperature * math.Pow(0.01, float64(i)/float64(a.iterations))
+
+		nextTasks := append([]string(nil), taskOrder...)
+		nextDevs := append([]string(nil), devOrder...)
+		if rng.Float64() < 0.7 || len(nextDevs) < 2 {
+			swapRandom(rng, nextTasks)
+		} else {
+			swapRandom(rng, nextDevs)
+		}
+
+		cost := evaluate(nextTasks, nextDevs)
+		if cost < current || rng.Float64() < math.Exp((current-cost)/temperature) {
+			taskOrder, devOrder, current = nextTasks, nextDevs, cost
+			if cost < bestCost {
+				bestCost = cost
+				bestTasks = append([]string(nil), nextTasks...)
+				bestDevs = append([]string(nil), nextDevs...)
+			}
+		}
+	}
+
+	s.debug("Optimizer best cost: %.2f", bestCost)
+	s.taskRank = rankOf(bestTasks)
+	s.developers = orderDevelopers(s.developers, bestDevs)
+	s.simulate(startDate)
+}
+
+// scheduleCost scores a finished simulation: the makespan in working days plus
+// a penalty for finishing high priority tasks late.
+func (s *Scheduler) scheduleCost(startDate time.Time) float64 {
+	var finish time.Time
+	weightedCompletion := 0.0
+	unfinished := 0
+	for _, task := range s.tasks {
+		if !task.IsCompleted {
+			unfinished++
+			continue
+		}
+		if task.EndTime.After(finish) {
+			finish = task.EndTime
+		}
+		weight := 1 / math.Max(float64(task.Priority), 1)
+		weightedCompletion += weight * float64(s.workdaysBetween(startDate, task.EndTime))
+	}
+
+	cost := float64(unfinished) * unfinishedTaskPenalty
+	if !finish.IsZero() {
+		cost += float64(s.workdaysBetween(startDate, finish))
+	}
+	if len(s.tasks) > 0 {
+		cost += priorityPenaltyWeight * weightedCompletion / float64(len(s.tasks))
+	}
+	return cost
+}
+
+func rankOf(names []string) map[string]int {
+	rank := make(map[string]int, len(names))
+	for i, name := range names {
+		rank[name] = i
+	}
+	return rank
+}
+
+// orderDevelopers returns devs rearranged to follow the given name order.
+func orderDevelopers(devs []*Developer, order []string) []*Developer {
+	byName := make(map[string]*Developer, len(devs))
+	for _, dev := range devs {
+		byName[dev.Name] = dev
+	}
+	ordered := make([]*Developer, 0, len(devs))
+	for _, name := range order {
+		if dev, ok := byName[name]; ok {
+			ordered = append(ordered, dev)
+		}
+	}
+	return ordered
+}
+
+func swapRandom(rng *rand.Rand, names []string) {
+	if len(names) < 2 {
+		return
+	}
+	i, j := rng.Intn(len(names)), rng.Intn(len(names))
+	names[i], names[j] = names[j], names[i]
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewScheduleStrategy(t *testing.T) {
+	for _, name := range []string{"", "greedy", "optimize"} {
+		if _, err := newScheduleStrategy(name); err != nil {
+			t.Errorf("newScheduleStrategy(%q): %v", name, err)
+		}
+	}
+	if _, err := newScheduleStrategy("random"); err == nil {
+		t.Errorf("newScheduleStrategy(%q) = nil error, want error", "random")
+	}
+}
+
+// newStrategyScheduler sets up a schedule the greedy pass gets wrong: the
+// only frontend developer comes first in the CSV and is taken by the backend
+// task, leaving the frontend task to wait.
+func newStrategyScheduler() *Scheduler {
+	tasks := []*Task{
+		{Name: "A", TaskType: "Backend", Priority: 1, ParallelFactor: 1, Effort: 4, RawEffort: 4},
+		{Name: "B", TaskType: "Frontend", Priority: 2, ParallelFactor: 1, Effort: 4, RawEffort: 4},
+	}
+	devs := []*Developer{
+		{Name: "Dev1", Role: "Senior", TaskTypes: []string{"Backend", "Frontend"}},
+		{Name: "Dev2", Role: "Senior", TaskTypes: []string{"Backend"}},
+	}
+	roles := map[string]*Role{"Senior": {Name: "Senior", AvailabilityPercent: 1}}
+	s := NewScheduler(tasks, devs, roles, nil, nil, nil)
+	s.quiet = true
+	return s
+}
+
+func TestScheduleStrategies(t *testing.T) {
+	tests := []struct {
+		name string
+		want time.Time
+	}{
+		{name: "greedy", want: time.Date(2026, 10, 21, 17, 0, 0, 0, time.UTC)},
+		{name: "optimize", want: time.Date(2026, 10, 15, 17, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStrategyScheduler()
+			strategy, err := newScheduleStrategy(tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			strategy.Schedule(s, monday)
+
+			var finish time.Time
+			for _, task := range s.tasks {
+				if !task.IsCompleted {
+					t.Fatalf("task %s not completed", task.Name)
+				}
+				if task.EndTime.After(finish) {
+					finish = task.EndTime
+				}
+			}
+			if !finish.Equal(tt.want) {
+				t.Errorf("finish = %v, want %v", finish, tt.want)
+			}
+		})
+	}
+}
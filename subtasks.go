@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parentRef names the task a subtask is derived from in DependsOn and Blocks.
+const parentRef = "parent"
+
+// SubtaskRule derives an extra task, named "<task>_<Name>", from every
+// tasks.csv row whose Column matches one of When.
+type SubtaskRule struct {
+	Name           string   `yaml:"name"`
+	Column         string   `yaml:"column"` // tasks.csv header of the triggering column, or a built-in column
+	When           []string `yaml:"when"`   // Matching values, "true" when empty
+	TaskType       string   `yaml:"taskType"`
+	EffortRatio    float64  `yaml:"effortRatio"`    // Share of the parent's effort
+	ParallelFactor int      `yaml:"parallelFactor"` // 1 when unset
+	DependsOn      []string `yaml:"dependsOn"`      // "parent" or other rule names, with an optional link like "parent:SS+3"
+	Blocks         []string `yaml:"blocks"`         // Tasks that depend on this subtask, same syntax as DependsOn
+	LinkColumn     string   `yaml:"linkColumn"`     // Optional column overriding the link type of DependsOn
+	OwnOverhead    bool     `yaml:"ownOverhead"`    // Inflate by the subtask's own overhead model instead of the parent's factor
+}
+
+// defaultSubtaskRules reproduces the built-in frontend and QA subtasks.
+var defaultSubtaskRules = []SubtaskRule{
+	{
+		Name:        "Frontend",
+		Column:      "NeedsFE",
+		TaskType:    "Frontend",
+		EffortRatio: 0.25,
+		DependsOn:   []string{parentRef},
+		LinkColumn:  "FrontendLink",
+	},
+	{
+		Name:        "QA",
+		Column:      "NeedsQA",
+		TaskType:    "QA",
+		EffortRatio: 0.25,
+		DependsOn:   []string{parentRef, "Frontend"},
+		LinkColumn:  "QALink",
+	},
+}
+
+// builtinColumns are the positions the NeedsFE and NeedsQA columns have
+// always had in tasks.csv. They are used when the header does not name them.
+var builtinColumns = map[string]int{
+	"NeedsFE": 6,
+	"NeedsQA": 7,
+}
+
+// optionalLinkColumns are the link columns of the built-in rules, which
+// tasks.csv may leave out.
+var optionalLinkColumns = map[string]bool{
+	"FrontendLink": true,
+	"QALink":       true,
+}
+
+// subtaskColumns maps tasks.csv column names to their index, falling back to
+// the built-in positions of NeedsFE and NeedsQA, and rejects rules whose
+// columns cannot be found. Link columns are only ever found by name.
+func subtaskColumns(header []string, rules []SubtaskRule) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for name, i := range builtinColumns {
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+
+	for _, rule := range rules {
+		if _, ok := columns[rule.Column]; !ok {
+			return nil, fmt.Errorf("subtask rule %s: column %q is not in the tasks.csv header", rule.Name, rule.Column)
+		}
+		if _, ok := columns[rule.LinkColumn]; rule.LinkColumn != "" && !ok && !optionalLinkColumns[rule.LinkColumn] {
+			return nil, fmt.Errorf("subtask rule %s: link column %q is not in the tasks.csv header", rule.Name, rule.LinkColumn)
+		}
+	}
+	return columns, nil
+}
+
+// loadSubtaskRules reads subtask rules from a YAML or JSON file.
+func loadSubtaskRules(filename string) ([]SubtaskRule, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var rules []SubtaskRule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid subtask rules: %v", err)
+	}
+	if err := validateSubtaskRules(rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func validateSubtaskRules(rules []SubtaskRule) error {
+	names := map[string]bool{parentRef: true}
+	for _, rule := range rules {
+		if rule.Name == "" || rule.Column == "" || rule.TaskType == "" {
+			return fmt.Errorf("subtask rule %q needs a name, column and taskType", rule.Name)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("duplicate subtask rule %q", rule.Name)
+		}
+		if rule.EffortRatio <= 0 {
+			return fmt.Errorf("subtask rule %s: effortRatio must be positive", rule.Name)
+		}
+		if rule.ParallelFactor < 0 {
+			return fmt.Errorf("subtask rule %s: parallelFactor cannot be negative", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+	for _, rule := range rules {
+		for _, entry := range append(append([]string{}, rule.DependsOn...), rule.Blocks...) {
+			link := parseDependency(entry)
+			if !names[link.Task] || link.Task == rule.Name {
+				return fmt.Errorf("subtask rule %s references unknown rule %q", rule.Name, link.Task)
+			}
+		}
+	}
+	return nil
+}
+
+// matches reports whether a tasks.csv cell triggers the rule. Cells may list
+// several values separated by semicolons.
+func (r SubtaskRule) matches(value string) bool {
+	when := r.When
+	if len(when) == 0 {
+		when = []string{"true"}
+	}
+	for _, v := range strings.Split(value, ";") {
+		for _, w := range when {
+			if strings.EqualFold(strings.TrimSpace(v), w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// overheadFactor returns the factor a subtask derived by the rule is inflated
+// by: its parent's, or its own model's when the rule asks for it.
+func (r SubtaskRule) overheadFactor(overhead OverheadConfig, parent, subtask *Task) float64 {
+	if r.OwnOverhead {
+		return overhead.factor(subtask)
+	}
+	return overhead.factor(parent)
+}
+
+// deriveSubtasks applies the rules to one tasks.csv row and returns the
+// generated subtasks. Links blocking the parent are added to it directly.
+// Each subtask's share of the raw effort is inflated by the parent's overhead
+// factor, or by its own model when the rule asks for it.
+func deriveSubtasks(parent *Task, estimate Estimate, rules []SubtaskRule, overhead OverheadConfig, record []string, columns map[string]int) ([]*Task, error) {
+	cell := func(column string) string {
+		if i, ok := columns[column]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	// Resolve which rules fire first so dependencies can refer to any of them
+	generated := map[string]*Task{parentRef: parent}
+	var subtasks []*Task
+	for _, rule := range rules {
+		if !rule.matches(cell(rule.Column)) {
+			continue
+		}
+		parallel := rule.ParallelFactor
+		if parallel == 0 {
+			parallel = 1
+		}
+		subtask := &Task{
+			Name:           parent.Name + "_" + rule.Name,
+			TaskType:       rule.TaskType,
+			Team:           parent.Team,
+			Priority:       parent.Priority,
+			RawEffort:      parent.RawEffort * rule.EffortRatio,
+			ParallelFactor: parallel,
+			Parent:         parent.Name,
+			Dependencies:   []string{},
+			DueDate:        parent.DueDate,
+		}
+		inflateBy(subtask, estimate.Scale(rule.EffortRatio), rule.overheadFactor(overhead, parent, subtask))
+		generated[rule.Name] = subtask
+		subtasks = append(subtasks, subtask)
+	}
+
+	for _, rule := range rules {
+		subtask, ok := generated[rule.Name]
+		if !ok {
+			continue
+		}
+
+		var override *DependencyLink
+		if spec := cell(rule.LinkColumn); rule.LinkColumn != "" && spec != "" {
+			linkType, lag, err := parseLinkSpec(spec)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s for task %s: %v", rule.LinkColumn, parent.Name, err)
+			}
+			override = &DependencyLink{Type: linkType, Lag: lag}
+		}
+
+		dependsOn := rule.DependsOn
+		if len(dependsOn) == 0 && len(rule.Blocks) == 0 {
+			dependsOn = []string{parentRef}
+		}
+		for _, entry := range dependsOn {
+			link := parseDependency(entry)
+			dep, ok := generated[link.Task]
+			if !ok {
+				continue // That rule did not fire for this row
+			}
+			link.Task = dep.Name
+			if override != nil {
+				link.Type, link.Lag = override.Type, override.Lag
+			}
+			subtask.Dependencies = append(subtask.Dependencies, link.Task)
+			subtask.Links = append(subtask.Links, link)
+		}
+
+		for _, entry := range rule.Blocks {
+			link := parseDependency(entry)
+			dependent, ok := generated[link.Task]
+			if !ok {
+				continue
+			}
+			link.Task = subtask.Name
+			dependent.Dependencies = append(dependent.Dependencies, link.Task)
+			dependent.Links = append(dependent.Links, link)
+		}
+	}
+
+	return subtasks, nil
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSubtaskColumns(t *testing.T) {
+	legacy := []string{"Name", "TaskType", "Priority", "Effort", "ParallelFactor", "Dependencies", "NeedsFE", "NeedsQA"}
+	full := append(append([]string{}, legacy...), "DueDate", "MinProficiency", "AllowedDevs", "ForbiddenDevs", "EarliestStart", "Team")
+	tests := []struct {
+		name    string
+		header  []string
+		rules   []SubtaskRule
+		want    map[string]int // Columns that must be found at these positions
+		missing []string       // Columns that must not be found
+		wantErr bool
+	}{
+		{
+			name:    "legacy header",
+			header:  legacy,
+			rules:   defaultSubtaskRules,
+			want:    map[string]int{"NeedsFE": 6, "NeedsQA": 7},
+			missing: []string{"FrontendLink", "QALink"},
+		},
+		{
+			name:    "unnamed built-in columns fall back to their positions",
+			header:  []string{"Name", "TaskType", "Priority", "Effort", "ParallelFactor", "Dependencies", "FE", "QA"},
+			rules:   defaultSubtaskRules,
+			want:    map[string]int{"NeedsFE": 6, "NeedsQA": 7},
+			missing: []string{"FrontendLink", "QALink"},
+		},
+		{
+			name:    "team column is not taken for a link column",
+			header:  full,
+			rules:   defaultSubtaskRules,
+			want:    map[string]int{"Team": 13},
+			missing: []string{"FrontendLink", "QALink"},
+		},
+		{
+			name:   "link columns found by name",
+			header: append(append([]string{}, full...), "QALink", "FrontendLink"),
+			rules:  defaultSubtaskRules,
+			want:   map[string]int{"QALink": 14, "FrontendLink": 15},
+		},
+		{
+			name:    "unknown rule column",
+			header:  legacy,
+			rules:   []SubtaskRule{{Name: "Docs", Column: "NeedsDocs", TaskType: "Docs", EffortRatio: 0.1}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown link column",
+			header:  legacy,
+			rules:   []SubtaskRule{{Name: "Docs", Column: "NeedsFE", TaskType: "Docs", EffortRatio: 0.1, LinkColumn: "DocsLink"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := subtaskColumns(tt.header, tt.rules)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for name, want := range tt.want {
+				if got, ok := columns[name]; !ok || got != want {
+					t.Errorf("column %s = %d (found %v), want %d", name, got, ok, want)
+				}
+			}
+			for _, name := range tt.missing {
+				if i, ok := columns[name]; ok {
+					t.Errorf("column %s found at %d, want none", name, i)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadTasksWithTeamColumn(t *testing.T) {
+	tasksFile := filepath.Join(t.TempDir(), "tasks.csv")
+	data := "Name,TaskType,Priority,Effort,ParallelFactor,Dependencies,NeedsFE,NeedsQA,DueDate,MinProficiency,AllowedDevs,ForbiddenDevs,EarliestStart,Team\n" +
+		"Checkout,Backend,1,4,1,,true,true,,,,,,Payments\n"
+	if err := os.WriteFile(tasksFile, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, _, _, err := LoadFromCSV("roles.csv", tasksFile, "developers.csv", defaultSubtaskRules, defaultOverhead)
+	if err != nil {
+		t.Fatalf("LoadFromCSV: %v", err)
+	}
+	var names []string
+	for _, task := range tasks {
+		names = append(names, task.Name)
+		if task.Team != "Payments" {
+			t.Errorf("%s team = %q, want Payments", task.Name, task.Team)
+		}
+	}
+	if want := []string{"Checkout", "Checkout_Frontend", "Checkout_QA"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("tasks = %v, want %v", names, want)
+	}
+}
+
+func TestDeriveSubtasks(t *testing.T) {
+	header := []string{"Name", "TaskType", "Priority", "Effort", "ParallelFactor", "Dependencies", "NeedsFE", "NeedsQA", "Platform", "FrontendLink"}
+	design := SubtaskRule{Name: "Design", Column: "Platform", When: []string{"mobile"}, TaskType: "Design", EffortRatio: 0.5, Blocks: []string{"parent:SS+1"}}
+	tests := []struct {
+		name     string
+		rules    []SubtaskRule
+		needsFE  string
+		needsQA  string
+		platform string
+		link     string
+		want     []string // "name type raw-effort: links" of each subtask
+		parent   []string // Links added to the parent
+		wantErr  bool
+	}{
+		{
+			name:  "frontend and QA",
+			rules: defaultSubtaskRules, needsFE: "true", needsQA: "TRUE",
+			want: []string{"A_Frontend Frontend 1: A:FS", "A_QA QA 1: A:FS A_Frontend:FS"},
+		},
+		{
+			name:  "QA only skips the frontend dependency",
+			rules: defaultSubtaskRules, needsFE: "false", needsQA: "true",
+			want: []string{"A_QA QA 1: A:FS"},
+		},
+		{
+			name:  "no subtasks",
+			rules: defaultSubtaskRules, needsFE: "false", needsQA: "",
+		},
+		{
+			name:  "link column overrides the link type",
+			rules: defaultSubtaskRules, needsFE: "true", link: "SS+2",
+			want: []string{"A_Frontend Frontend 1: A:SS+2"},
+		},
+		{
+			name:  "invalid link",
+			rules: defaultSubtaskRules, needsFE: "true", link: "later",
+			wantErr: true,
+		},
+		{
+			name:  "one of several values blocks the parent",
+			rules: []SubtaskRule{design}, platform: "web; Mobile",
+			want:   []string{"A_Design Design 2: "},
+			parent: []string{"A_Design:SS+1"},
+		},
+		{
+			name:  "no matching value",
+			rules: []SubtaskRule{design}, platform: "web",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := subtaskColumns(header, tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parent := &Task{Name: "A", TaskType: "Backend", Priority: 2, ParallelFactor: 1, RawEffort: 4}
+			record := []string{"A", "Backend", "2", "4", "1", "", tt.needsFE, tt.needsQA, tt.platform, tt.link}
+			subtasks, err := deriveSubtasks(parent, Estimate{}, tt.rules, defaultOverhead, record, columns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var got []string
+			for _, subtask := range subtasks {
+				if subtask.Parent != "A" || subtask.Priority != 2 {
+					t.Errorf("%s parent, priority = %s, %d, want A, 2", subtask.Name, subtask.Parent, subtask.Priority)
+				}
+				got = append(got, fmt.Sprintf("%s %s %v: %s", subtask.Name, subtask.TaskType, subtask.RawEffort, formatLinks(subtask.Links)))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("subtasks = %q, want %q", got, tt.want)
+			}
+			if got := formatLinks(parent.Links); got != strings.Join(tt.parent, " ") {
+				t.Errorf("parent links = %q, want %q", got, tt.parent)
+			}
+		})
+	}
+}
+
+// formatLinks writes links as "task:FS+2", separated by spaces.
+func formatLinks(links []DependencyLink) string {
+	var parts []string
+	for _, link := range links {
+		parts = append(parts, link.Task+":"+link.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestLoadSubtaskRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr bool
+	}{
+		{name: "valid", yaml: "- {name: Docs, column: NeedsDocs, taskType: Docs, effortRatio: 0.1, dependsOn: [parent]}"},
+		{name: "not a list", yaml: "name: Docs", wantErr: true},
+		{name: "missing task type", yaml: "- {name: Docs, column: NeedsDocs, effortRatio: 0.1}", wantErr: true},
+		{name: "no effort", yaml: "- {name: Docs, column: NeedsDocs, taskType: Docs}", wantErr: true},
+		{name: "negative parallel factor", yaml: "- {name: Docs, column: NeedsDocs, taskType: Docs, effortRatio: 0.1, parallelFactor: -1}", wantErr: true},
+		{name: "duplicate", yaml: "- {name: Docs, column: A, taskType: Docs, effortRatio: 0.1}\n- {name: Docs, column: B, taskType: Docs, effortRatio: 0.1}", wantErr: true},
+		{name: "named parent", yaml: "- {name: parent, column: A, taskType: Docs, effortRatio: 0.1}", wantErr: true},
+		{name: "unknown dependency", yaml: "- {name: Docs, column: A, taskType: Docs, effortRatio: 0.1, dependsOn: [Review]}", wantErr: true},
+		{name: "depends on itself", yaml: "- {name: Docs, column: A, taskType: Docs, effortRatio: 0.1, blocks: ['Docs:SS']}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "subtasks.yaml")
+			if err := os.WriteFile(filename, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := loadSubtaskRules(filename); (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSampleSubtaskRules(t *testing.T) {
+	rules, err := loadSubtaskRules("subtasks.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rules, defaultSubtaskRules) {
+		t.Errorf("subtasks.yaml = %+v, want the built-in rules %+v", rules, defaultSubtaskRules)
+	}
+}
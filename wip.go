@@ -0,0 +1,62 @@
+package main
+
+import "time"
+
+// maxTasks returns how many tasks a developer may work on at once.
+func (s *Scheduler) maxTasks(dev *Developer) int {
+	if dev.MaxTasks < 1 {
+		return 1
+	}
+	return dev.MaxTasks
+}
+
+// activeTasks counts the tasks with work left that a developer is on at date.
+func (s *Scheduler) activeTasks(dev *Developer, date time.Time) int {
+	active := 0
+	for _, task := range s.tasks {
+		if task.isAssigned(dev.Name) && !task.IsCompleted && task.remainingEffort() > effortEpsilon {
+			active++
+		}
+	}
+	return active
+}
+
+// hasFreeSlot reports whether a developer can take on another task at date
+// without exceeding their WIP limit.
+func (s *Scheduler) hasFreeSlot(dev *Developer, date time.Time) bool {
+	return s.activeTasks(dev, date) < s.maxTasks(dev)
+}
+
+// dailyShare returns the share of a developer's capacity each of their active
+// tasks receives on date. Capacity is split evenly across the tasks they are
+// actually working on, not their WIP limit.
+func (s *Scheduler) dailyShare(dev *Developer, date time.Time) float64 {
+	return s.splitShare(max(s.activeTasks(dev, date), 1))
+}
+
+// assignmentShare returns the share of a developer's capacity a task picked
+// up at date starts out with, alongside the tasks already in flight.
+func (s *Scheduler) assignmentShare(dev *Developer, date time.Time) float64 {
+	return s.splitShare(s.activeTasks(dev, date) + 1)
+}
+
+// splitShare is the share of capacity each of n tasks receives. Juggling more
+// than one task pays the context-switch penalty.
+func (s *Scheduler) splitShare(n int) float64 {
+	share := 1 / float64(n)
+	if n > 1 {
+		share *= 1 - s.contextSwitchPenalty
+	}
+	return share
+}
+
+// refreshNextFreeTime sets when a developer is next free of all work.
+func (s *Scheduler) refreshNextFreeTime(dev *Developer) {
+	var free time.Time
+	for _, task := range s.tasks {
+		if task.isAssigned(dev.Name) && !task.IsCompleted && task.EndTime.After(free) {
+			free = task.EndTime
+		}
+	}
+	dev.NextFreeTime = free
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// newWIPScheduler returns a scheduler with one full-time backend developer
+// and the given tasks, each with its effort in days.
+func newWIPScheduler(maxTasks int, penalty float64, efforts ...float64) *Scheduler {
+	var tasks []*Task
+	for i, effort := range efforts {
+		tasks = append(tasks, &Task{
+			Name:           string(rune('A' + i)),
+			TaskType:       "Backend",
+			Priority:       1,
+			ParallelFactor: 1,
+			Effort:         effort,
+			RawEffort:      effort,
+		})
+	}
+	devs := []*Developer{{Name: "Dev1", Role: "Senior", TaskTypes: []string{"Backend"}, MaxTasks: maxTasks}}
+	roles := map[string]*Role{"Senior": {Name: "Senior", AvailabilityPercent: 1}}
+	s := NewScheduler(tasks, devs, roles, nil, nil, nil)
+	s.quiet = true
+	s.contextSwitchPenalty = penalty
+	return s
+}
+
+func TestLoneTaskIgnoresWIPLimit(t *testing.T) {
+	want := time.Date(2026, 10, 15, 17, 0, 0, 0, time.UTC) // 4 days from Monday
+	for _, maxTasks := range []int{1, 2, 4} {
+		s := newWIPScheduler(maxTasks, 0.2, 4)
+		s.simulate(monday)
+		if got := s.tasks[0].EndTime; !got.Equal(want) {
+			t.Errorf("MaxTasks=%d: end = %v, want %v", maxTasks, got, want)
+		}
+	}
+}
+
+func TestWIPSplitsCapacity(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxTasks int
+		penalty  float64
+		ends     []time.Time
+	}{
+		{
+			name:     "one at a time",
+			maxTasks: 1,
+			ends: []time.Time{
+				time.Date(2026, 10, 13, 17, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 15, 17, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "side by side",
+			maxTasks: 2,
+			ends: []time.Time{
+				time.Date(2026, 10, 15, 13, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 15, 17, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// Half a day each less 20%, so 3.2 hours a day on each task
+			name:     "side by side with a context-switch penalty",
+			maxTasks: 2,
+			penalty:  0.2,
+			ends: []time.Time{
+				time.Date(2026, 10, 16, 12, 12, 0, 0, time.UTC),
+				time.Date(2026, 10, 16, 15, 24, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newWIPScheduler(tt.maxTasks, tt.penalty, 2, 2)
+			s.simulate(monday)
+			for i, want := range tt.ends {
+				if got := s.tasks[i].EndTime; !got.Equal(want) {
+					t.Errorf("%s end = %v, want %v", s.tasks[i].Name, got, want)
+				}
+			}
+		})
+	}
+}